@@ -0,0 +1,201 @@
+// Command nego-eval replays scripted buyer conversations through the Smart-Nego
+// Negotiator and scores the seller's behaviour, so prompt changes can be
+// compared offline.
+//
+//	go run ./cmd/nego-eval                  # replay from the cassette, no network
+//	go run ./cmd/nego-eval -record          # call Gemini (GEMINI_API_KEY or CONFIG_FILE) and refresh the cassette
+//	go run ./cmd/nego-eval -prompt v2 -json # evaluate another prompt version
+//
+// Recordings are keyed by prompt version, scenario and turn. The committed
+// cassette holds hand-written baseline replies for v1; -record replaces them
+// with real model output.
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"hackathon-backend/config"
+	"hackathon-backend/pkg/gemini"
+	"log"
+	"os"
+	"strings"
+)
+
+// scenario is one line of the fixtures file.
+type scenario struct {
+	Name string `json:"name"`
+	Item struct {
+		InitialPrice int    `json:"initial_price"`
+		CurrentPrice int    `json:"current_price"`
+		MinPrice     int    `json:"min_price"`
+		Views        int    `json:"views"`
+		DaysListed   int    `json:"days_listed"`
+		Description  string `json:"description"`
+	} `json:"item"`
+	BuyerTurns []string `json:"buyer_turns"`
+}
+
+// turn is the Negotiator's answer to one scripted buyer message.
+type turn struct {
+	Buyer          string                      `json:"buyer"`
+	Response       *gemini.NegotiationResponse `json:"response,omitempty"`
+	SuggestedPrice int                         `json:"suggested_price,omitempty"`
+	Error          string                      `json:"error,omitempty"`
+}
+
+func main() {
+	fixtures := flag.String("fixtures", "cmd/nego-eval/testdata/scenarios.jsonl", "scripted buyer conversations (JSONL)")
+	cassettePath := flag.String("cassette", "cmd/nego-eval/testdata/cassette.jsonl", "recorded LLM responses (JSONL)")
+	record := flag.Bool("record", false, "call Gemini and (re)record the cassette; needs GEMINI_API_KEY")
+	promptVersion := flag.String("prompt", gemini.DefaultPromptVersion, "prompt template version to evaluate")
+	asJSON := flag.Bool("json", false, "print full results as JSON")
+	flag.Parse()
+
+	scenarios, err := loadScenarios(*fixtures)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	prompts, err := gemini.NewPromptRegistry()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if !prompts.Has(*promptVersion) {
+		log.Fatalf("unknown prompt version %q (available: %s)", *promptVersion, strings.Join(prompts.Versions(), ", "))
+	}
+
+	ctx := context.Background()
+	mode := gemini.CassetteReplay
+	var inner gemini.TextGenerator
+	if *record {
+		cfg, err := config.Load()
+		if err != nil {
+			log.Fatal("Invalid configuration:\n", err)
+		}
+		if cfg.Gemini.APIKey == "" {
+			log.Fatal("-record needs GEMINI_API_KEY")
+		}
+		inner, err = gemini.NewModelGenerator(ctx, cfg.Gemini.APIKey)
+		if err != nil {
+			log.Fatal(err)
+		}
+		mode = gemini.CassetteRecord
+	}
+	cassette, err := gemini.OpenCassette(*cassettePath, mode, inner)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var negotiator gemini.Negotiator = gemini.NewClientWithGenerator(cassette, prompts)
+
+	var results []result
+	for _, sc := range scenarios {
+		turns := run(ctx, negotiator, *promptVersion, sc)
+		results = append(results, score(sc, turns))
+	}
+
+	if err := cassette.Save(); err != nil {
+		log.Fatal("Failed to save cassette:", err)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		enc.Encode(results)
+	} else {
+		printReport(os.Stdout, *promptVersion, results)
+	}
+
+	// Selling below the floor or failing to answer at all is a hard failure
+	for _, r := range results {
+		if r.FloorViolations > 0 || r.Errors > 0 {
+			os.Exit(1)
+		}
+	}
+}
+
+func loadScenarios(path string) ([]scenario, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var scenarios []scenario
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		var sc scenario
+		if err := json.Unmarshal([]byte(text), &sc); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		scenarios = append(scenarios, sc)
+	}
+	return scenarios, scanner.Err()
+}
+
+// run plays the buyer's scripted turns against the Negotiator, feeding back its
+// replies as history and applying suggested prices the way seller approval does.
+func run(ctx context.Context, negotiator gemini.Negotiator, promptVersion string, sc scenario) []turn {
+	var history []gemini.MessageHistory
+	currentPrice := sc.Item.CurrentPrice
+	if currentPrice == 0 {
+		currentPrice = sc.Item.InitialPrice
+	}
+
+	var turns []turn
+	for i, buyerMsg := range sc.BuyerTurns {
+		t := turn{Buyer: buyerMsg}
+		// Recordings are named by prompt version, scenario and turn, so template edits don't orphan them
+		turnCtx := gemini.WithCassetteKey(ctx, fmt.Sprintf("%s/%s/%d", promptVersion, sc.Name, i+1))
+		resp, err := negotiator.GenerateNegotiationResponse(turnCtx, promptVersion, gemini.NegotiationPromptData{
+			InitialPrice:    sc.Item.InitialPrice,
+			CurrentPrice:    currentPrice,
+			MinPrice:        sc.Item.MinPrice,
+			Views:           sc.Item.Views,
+			DaysListed:      sc.Item.DaysListed,
+			ItemDescription: sc.Item.Description,
+			History:         history,
+			CurrentMessage:  buyerMsg,
+		})
+		if err != nil {
+			t.Error = err.Error()
+			turns = append(turns, t)
+			// Later turns depend on this reply, so the rest of the script is meaningless
+			break
+		}
+		t.Response = resp
+		t.SuggestedPrice = suggestedPrice(resp)
+		if t.SuggestedPrice > 0 {
+			currentPrice = t.SuggestedPrice
+		}
+		turns = append(turns, t)
+
+		history = append(history,
+			gemini.MessageHistory{Sender: "Buyer", Content: buyerMsg},
+			gemini.MessageHistory{Sender: "Seller", Content: resp.ResponseContent},
+		)
+	}
+	return turns
+}
+
+// suggestedPrice mirrors how the item usecase derives a message's suggested price.
+func suggestedPrice(resp *gemini.NegotiationResponse) int {
+	decision := strings.ToLower(resp.Decision)
+	if (decision == "agreement" || decision == "accept") && resp.DetectedPrice > 0 {
+		return resp.DetectedPrice
+	}
+	if resp.CounterPrice > 0 {
+		return resp.CounterPrice
+	}
+	return 0
+}
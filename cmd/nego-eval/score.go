@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+)
+
+// result is the score of one scenario run.
+type result struct {
+	Scenario        string   `json:"scenario"`
+	Turns           []turn   `json:"turns"`
+	FloorViolations int      `json:"floor_violations"`     // Offers or acceptances below the minimum price
+	NonMonotonic    int      `json:"non_monotonic_offers"` // Offers higher than an earlier offer
+	FinalPrice      int      `json:"final_price"`
+	FinalPriceRatio float64  `json:"final_price_ratio"` // FinalPrice / initial price
+	LanguageIssues  int      `json:"language_issues"`   // Replies not in polite Japanese
+	Errors          int      `json:"errors"`
+	Notes           []string `json:"notes,omitempty"`
+}
+
+var validDecisions = map[string]bool{"ACCEPT": true, "REJECT": true, "COUNTER": true, "ANSWER": true}
+
+// Endings that mark a reply as teineigo/keigo rather than casual speech.
+var politeMarkers = []string{"です", "ます", "ございます", "ください", "ません", "でしょうか"}
+
+func score(sc scenario, turns []turn) result {
+	r := result{Scenario: sc.Name, Turns: turns}
+
+	lastOffer := 0
+	finalPrice := sc.Item.CurrentPrice
+	if finalPrice == 0 {
+		finalPrice = sc.Item.InitialPrice
+	}
+
+	for i, t := range turns {
+		n := i + 1
+		if t.Error != "" {
+			r.Errors++
+			r.Notes = append(r.Notes, fmt.Sprintf("turn %d: %s", n, t.Error))
+			continue
+		}
+		resp := t.Response
+		decision := strings.ToUpper(resp.Decision)
+		if !validDecisions[decision] {
+			r.Notes = append(r.Notes, fmt.Sprintf("turn %d: unexpected decision %q", n, resp.Decision))
+		}
+
+		// Floor: never counter or accept below the seller's limit
+		if t.SuggestedPrice > 0 && t.SuggestedPrice < sc.Item.MinPrice {
+			r.FloorViolations++
+			r.Notes = append(r.Notes, fmt.Sprintf("turn %d: %s at ¥%d is below floor ¥%d", n, decision, t.SuggestedPrice, sc.Item.MinPrice))
+		}
+
+		// Concessions only go down: a later offer must not exceed an earlier one
+		if t.SuggestedPrice > 0 {
+			if lastOffer > 0 && t.SuggestedPrice > lastOffer {
+				r.NonMonotonic++
+				r.Notes = append(r.Notes, fmt.Sprintf("turn %d: offer ¥%d is higher than earlier ¥%d", n, t.SuggestedPrice, lastOffer))
+			}
+			lastOffer = t.SuggestedPrice
+			finalPrice = t.SuggestedPrice
+		}
+
+		if issue := languageIssue(resp.ResponseContent, resp.Reasoning); issue != "" {
+			r.LanguageIssues++
+			r.Notes = append(r.Notes, fmt.Sprintf("turn %d: %s", n, issue))
+		}
+	}
+
+	r.FinalPrice = finalPrice
+	if sc.Item.InitialPrice > 0 {
+		r.FinalPriceRatio = float64(finalPrice) / float64(sc.Item.InitialPrice)
+	}
+	return r
+}
+
+// languageIssue checks the buyer-facing reply is polite Japanese and the
+// seller-facing reasoning is Japanese, as the prompt requires.
+func languageIssue(content, reasoning string) string {
+	if !hasJapanese(content) {
+		return "reply is not in Japanese"
+	}
+	polite := false
+	for _, m := range politeMarkers {
+		if strings.Contains(content, m) {
+			polite = true
+			break
+		}
+	}
+	if !polite {
+		return "reply is not in polite form (keigo)"
+	}
+	if !hasJapanese(reasoning) {
+		return "reasoning is not in Japanese"
+	}
+	return ""
+}
+
+func hasJapanese(s string) bool {
+	for _, r := range s {
+		if unicode.In(r, unicode.Hiragana, unicode.Katakana, unicode.Han) {
+			return true
+		}
+	}
+	return false
+}
+
+func printReport(w io.Writer, promptVersion string, results []result) {
+	fmt.Fprintf(w, "Smart-Nego evaluation (prompt %s)\n\n", promptVersion)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SCENARIO\tTURNS\tFLOOR\tNON-MONOTONIC\tFINAL\tFINAL/INITIAL\tLANGUAGE\tERRORS")
+	var floor, nonMono, lang, errs int
+	var ratioSum float64
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t¥%d\t%.2f\t%d\t%d\n",
+			r.Scenario, len(r.Turns), r.FloorViolations, r.NonMonotonic, r.FinalPrice, r.FinalPriceRatio, r.LanguageIssues, r.Errors)
+		floor += r.FloorViolations
+		nonMono += r.NonMonotonic
+		lang += r.LanguageIssues
+		errs += r.Errors
+		ratioSum += r.FinalPriceRatio
+	}
+	avgRatio := 0.0
+	if len(results) > 0 {
+		avgRatio = ratioSum / float64(len(results))
+	}
+	fmt.Fprintf(tw, "TOTAL\t\t%d\t%d\t\t%.2f\t%d\t%d\n", floor, nonMono, avgRatio, lang, errs)
+	tw.Flush()
+
+	for _, r := range results {
+		for _, note := range r.Notes {
+			fmt.Fprintf(w, "- %s: %s\n", r.Scenario, note)
+		}
+	}
+}
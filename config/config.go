@@ -0,0 +1,240 @@
+// Package config loads the settings shared by the server, the migration
+// programs and the CLI tools: defaults, then an optional YAML file named by
+// CONFIG_FILE, then environment variables, which win.
+package config
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	Port      string          `yaml:"port" env:"PORT"`
+	DB        DBConfig        `yaml:"db"`
+	HTTP      HTTPConfig      `yaml:"http"`
+	CORS      CORSConfig      `yaml:"cors"`
+	Deadlines DeadlinesConfig `yaml:"deadlines"`
+	Gemini    GeminiConfig    `yaml:"gemini"`
+	AIWorkers int             `yaml:"ai_workers" env:"AI_WORKERS"`
+	SMTP      SMTPConfig      `yaml:"smtp"`
+	WebPush   WebPushConfig   `yaml:"web_push"`
+	Addresses AddressesConfig `yaml:"addresses"`
+	Admin     AdminConfig     `yaml:"admin"`
+}
+
+type DBConfig struct {
+	User            string        `yaml:"user" env:"MYSQL_USER"`
+	Password        string        `yaml:"password" env:"MYSQL_PWD" secret:"true"`
+	Host            string        `yaml:"host" env:"MYSQL_HOST"` // e.g. tcp(127.0.0.1:3306)
+	Name            string        `yaml:"name" env:"MYSQL_DATABASE"`
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME"`
+}
+
+// DSN is the go-sql-driver/mysql data source name.
+func (c DBConfig) DSN() string {
+	return fmt.Sprintf("%s:%s@%s/%s?parseTime=true&loc=Local", c.User, c.Password, c.Host, c.Name)
+}
+
+type HTTPConfig struct {
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT"`
+	ReadTimeout       time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT"` // 0: deadlines.llm + deadlines.write
+	IdleTimeout       time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+}
+
+type CORSConfig struct {
+	AllowedOrigins []string `yaml:"allowed_origins" env:"CORS_ALLOWED_ORIGINS"` // Comma-separated in env; "*" allows any
+}
+
+type DeadlinesConfig struct {
+	Read  time.Duration `yaml:"read" env:"DEADLINE_READ"`
+	Write time.Duration `yaml:"write" env:"DEADLINE_WRITE"`
+	LLM   time.Duration `yaml:"llm" env:"DEADLINE_LLM"`
+}
+
+type GeminiConfig struct {
+	APIKey               string `yaml:"api_key" env:"GEMINI_API_KEY" secret:"true"` // Empty disables Smart-Nego
+	PromptDefaultVersion string `yaml:"prompt_default_version" env:"PROMPT_DEFAULT_VERSION"`
+	PromptExperiment     string `yaml:"prompt_experiment" env:"PROMPT_EXPERIMENT"` // e.g. v1:90,v2:10
+}
+
+// SMTPConfig enables email notifications when Host is set.
+type SMTPConfig struct {
+	Host     string `yaml:"host" env:"SMTP_HOST"`
+	Port     string `yaml:"port" env:"SMTP_PORT"`
+	From     string `yaml:"from" env:"SMTP_FROM"`
+	Username string `yaml:"username" env:"SMTP_USERNAME"`
+	Password string `yaml:"password" env:"SMTP_PASSWORD" secret:"true"`
+}
+
+// WebPushConfig enables web push notifications when both keys are set.
+type WebPushConfig struct {
+	VAPIDPublicKey  string `yaml:"vapid_public_key" env:"VAPID_PUBLIC_KEY"`
+	VAPIDPrivateKey string `yaml:"vapid_private_key" env:"VAPID_PRIVATE_KEY" secret:"true"`
+	Subscriber      string `yaml:"subscriber" env:"VAPID_SUBSCRIBER"`
+}
+
+// AddressesConfig enables the shipping address book when EncryptionKey is set.
+type AddressesConfig struct {
+	EncryptionKey string `yaml:"encryption_key" env:"ADDRESS_ENCRYPTION_KEY" secret:"true"` // base64 of 32 bytes, e.g. openssl rand -base64 32
+}
+
+// AdminConfig names the users who may resolve order disputes.
+type AdminConfig struct {
+	UserIDs []string `yaml:"user_ids" env:"ADMIN_USER_IDS"` // Comma-separated in env
+}
+
+// Default is the local development setup (docker-compose) without the
+// database credentials: they must come from MYSQL_USER and MYSQL_PWD or the
+// config file (config.example.yaml has the docker-compose ones).
+func Default() Config {
+	return Config{
+		Port: "8080",
+		DB: DBConfig{
+			Host:            "tcp(127.0.0.1:3306)",
+			Name:            "hackathon_db",
+			MaxOpenConns:    25,
+			MaxIdleConns:    25,
+			ConnMaxLifetime: 5 * time.Minute, // Recycle before MySQL's wait_timeout closes them
+			ConnMaxIdleTime: time.Minute,
+		},
+		HTTP: HTTPConfig{
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       15 * time.Second,
+			IdleTimeout:       2 * time.Minute,
+			ShutdownTimeout:   30 * time.Second,
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: []string{"*"},
+		},
+		Deadlines: DeadlinesConfig{
+			Read:  5 * time.Second,
+			Write: 10 * time.Second,
+			LLM:   45 * time.Second,
+		},
+		AIWorkers: 4,
+		SMTP: SMTPConfig{
+			Port: "1025",
+			From: "no-reply@localhost",
+		},
+	}
+}
+
+// Load reads the configuration and validates it.
+func Load() (*Config, error) {
+	cfg := Default()
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if err := applyEnv(&cfg); err != nil {
+		return nil, err
+	}
+	if cfg.HTTP.WriteTimeout == 0 {
+		// A synchronous Gemini call (regenerate) must fit in one response
+		cfg.HTTP.WriteTimeout = cfg.Deadlines.LLM + cfg.Deadlines.Write
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+	defer f.Close()
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true) // Catch misspelt keys instead of silently using defaults
+	if err := dec.Decode(c); err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Validate reports every invalid setting at once.
+func (c *Config) Validate() error {
+	var errs []error
+	if port, err := strconv.Atoi(c.Port); err != nil || port < 1 || port > 65535 {
+		errs = append(errs, fmt.Errorf("PORT: %q is not a port number", c.Port))
+	}
+	if c.DB.User == "" {
+		errs = append(errs, errors.New("MYSQL_USER is required"))
+	}
+	if c.DB.Password == "" {
+		errs = append(errs, errors.New("MYSQL_PWD is required"))
+	}
+	if c.DB.Host == "" {
+		errs = append(errs, errors.New("MYSQL_HOST is required"))
+	}
+	if c.DB.Name == "" {
+		errs = append(errs, errors.New("MYSQL_DATABASE is required"))
+	}
+	if c.DB.MaxOpenConns < 0 || c.DB.MaxIdleConns < 0 {
+		errs = append(errs, errors.New("DB_MAX_OPEN_CONNS and DB_MAX_IDLE_CONNS must not be negative"))
+	}
+	for name, d := range map[string]time.Duration{
+		"DB_CONN_MAX_LIFETIME":     c.DB.ConnMaxLifetime,
+		"DB_CONN_MAX_IDLE_TIME":    c.DB.ConnMaxIdleTime,
+		"HTTP_READ_HEADER_TIMEOUT": c.HTTP.ReadHeaderTimeout,
+		"HTTP_READ_TIMEOUT":        c.HTTP.ReadTimeout,
+		"HTTP_WRITE_TIMEOUT":       c.HTTP.WriteTimeout,
+		"HTTP_IDLE_TIMEOUT":        c.HTTP.IdleTimeout,
+		"SHUTDOWN_TIMEOUT":         c.HTTP.ShutdownTimeout,
+		"DEADLINE_READ":            c.Deadlines.Read,
+		"DEADLINE_WRITE":           c.Deadlines.Write,
+		"DEADLINE_LLM":             c.Deadlines.LLM,
+	} {
+		if d < 0 {
+			errs = append(errs, fmt.Errorf("%s must not be negative", name))
+		}
+	}
+	if len(c.CORS.AllowedOrigins) == 0 {
+		errs = append(errs, errors.New(`CORS_ALLOWED_ORIGINS must list at least one origin (or "*")`))
+	}
+	for _, o := range c.CORS.AllowedOrigins {
+		if o != "*" && !strings.HasPrefix(o, "http://") && !strings.HasPrefix(o, "https://") {
+			errs = append(errs, fmt.Errorf("CORS_ALLOWED_ORIGINS: %q is not an origin like https://example.com", o))
+		}
+	}
+	if c.AIWorkers < 1 {
+		errs = append(errs, errors.New("AI_WORKERS must be at least 1"))
+	}
+	if c.SMTP.Host != "" && (c.SMTP.Port == "" || c.SMTP.From == "") {
+		errs = append(errs, errors.New("SMTP_PORT and SMTP_FROM are required with SMTP_HOST"))
+	}
+	if (c.WebPush.VAPIDPublicKey == "") != (c.WebPush.VAPIDPrivateKey == "") {
+		errs = append(errs, errors.New("VAPID_PUBLIC_KEY and VAPID_PRIVATE_KEY must be set together"))
+	}
+	if c.Addresses.EncryptionKey != "" {
+		if key, err := base64.StdEncoding.DecodeString(c.Addresses.EncryptionKey); err != nil || len(key) != 32 {
+			errs = append(errs, errors.New("ADDRESS_ENCRYPTION_KEY must be 32 bytes in base64 (openssl rand -base64 32)"))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// String prints the configuration as YAML with secrets redacted, for startup logs.
+func (c Config) String() string {
+	redacted := c
+	redact(&redacted)
+	out, err := yaml.Marshal(redacted)
+	if err != nil {
+		return err.Error()
+	}
+	return string(out)
+}
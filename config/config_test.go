@@ -0,0 +1,19 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateRequiresDBCredentials(t *testing.T) {
+	cfg := Default()
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "MYSQL_USER") || !strings.Contains(err.Error(), "MYSQL_PWD") {
+		t.Fatalf("Validate on the defaults = %v, want the database credentials reported missing", err)
+	}
+
+	cfg.DB.User, cfg.DB.Password = "user", "password"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate with credentials: %v", err)
+	}
+}
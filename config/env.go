@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// applyEnv overrides every field tagged `env:"NAME"` whose variable is set.
+func applyEnv(c *Config) error {
+	return walk(reflect.ValueOf(c).Elem(), func(f reflect.StructField, v reflect.Value) error {
+		name := f.Tag.Get("env")
+		if name == "" {
+			return nil
+		}
+		raw, ok := os.LookupEnv(name)
+		if !ok || raw == "" {
+			return nil
+		}
+		switch {
+		case v.Type() == durationType:
+			d, err := time.ParseDuration(raw)
+			if err != nil {
+				return fmt.Errorf("%s: %q is not a duration (e.g. 30s)", name, raw)
+			}
+			v.SetInt(int64(d))
+		case v.Kind() == reflect.Int:
+			n, err := strconv.Atoi(raw)
+			if err != nil {
+				return fmt.Errorf("%s: %q is not a number", name, raw)
+			}
+			v.SetInt(int64(n))
+		case v.Kind() == reflect.String:
+			v.SetString(raw)
+		case v.Type() == reflect.TypeOf([]string(nil)):
+			var list []string
+			for _, item := range strings.Split(raw, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					list = append(list, item)
+				}
+			}
+			v.Set(reflect.ValueOf(list))
+		default:
+			return fmt.Errorf("%s: unsupported setting type %s", name, v.Type())
+		}
+		return nil
+	})
+}
+
+// redact blanks out every non-empty field tagged `secret:"true"`.
+func redact(c *Config) {
+	walk(reflect.ValueOf(c).Elem(), func(f reflect.StructField, v reflect.Value) error {
+		if f.Tag.Get("secret") == "true" && v.String() != "" {
+			v.SetString("********")
+		}
+		return nil
+	})
+}
+
+// walk calls fn for every leaf field, descending into nested config structs.
+func walk(v reflect.Value, fn func(reflect.StructField, reflect.Value) error) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f, fv := t.Field(i), v.Field(i)
+		if fv.Kind() == reflect.Struct {
+			if err := walk(fv, fn); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := fn(f, fv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"encoding/json"
+	"hackathon-backend/model"
+	"hackathon-backend/usecase"
+	"net/http"
+)
+
+type AddressController struct {
+	usecase *usecase.AddressUsecase
+}
+
+func NewAddressController(usecase *usecase.AddressUsecase) *AddressController {
+	return &AddressController{usecase: usecase}
+}
+
+// ListAddresses serves GET /addresses?user_id=
+func (c *AddressController) ListAddresses(w http.ResponseWriter, r *http.Request) {
+	addresses, err := c.usecase.GetAddresses(r.Context(), r.URL.Query().Get("user_id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(addresses)
+}
+
+// CreateAddress serves POST /addresses with {"user_id", "name", "postal_code", "prefecture", "city", "line1", ...}
+func (c *AddressController) CreateAddress(w http.ResponseWriter, r *http.Request) {
+	var req model.Address
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	address, err := c.usecase.CreateAddress(r.Context(), req.UserID, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(address)
+}
+
+// UpdateAddress serves PUT /addresses/{id}: every field is replaced
+func (c *AddressController) UpdateAddress(w http.ResponseWriter, r *http.Request) {
+	var req model.Address
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	address, err := c.usecase.UpdateAddress(r.Context(), r.PathValue("id"), req.UserID, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(address)
+}
+
+// DeleteAddress serves DELETE /addresses/{id}?user_id=
+func (c *AddressController) DeleteAddress(w http.ResponseWriter, r *http.Request) {
+	if err := c.usecase.DeleteAddress(r.Context(), r.PathValue("id"), r.URL.Query().Get("user_id")); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"status": "deleted"}`))
+}
+
+// GetPurchaseAddress serves GET /items/{id}/shipping-address?user_id= : where a
+// sold item ships to, for its seller (and buyer)
+func (c *AddressController) GetPurchaseAddress(w http.ResponseWriter, r *http.Request) {
+	address, err := c.usecase.GetPurchaseAddress(r.Context(), r.PathValue("id"), r.URL.Query().Get("user_id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(address)
+}
@@ -0,0 +1,32 @@
+package controller
+
+import (
+	"encoding/json"
+	"hackathon-backend/usecase"
+	"net/http"
+	"strconv"
+)
+
+type AnalyticsController struct {
+	usecase *usecase.AnalyticsUsecase
+}
+
+func NewAnalyticsController(usecase *usecase.AnalyticsUsecase) *AnalyticsController {
+	return &AnalyticsController{usecase: usecase}
+}
+
+// GetEventCounts serves GET /analytics/events?days=30
+func (c *AnalyticsController) GetEventCounts(w http.ResponseWriter, r *http.Request) {
+	days, _ := strconv.Atoi(r.URL.Query().Get("days"))
+	counts, err := c.usecase.GetDailyCounts(r.Context(), days)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if counts == nil {
+		w.Write([]byte(`{"counts": []}`))
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"counts": counts})
+}
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"encoding/json"
+	"hackathon-backend/usecase"
+	"net/http"
+)
+
+type CategoryController struct {
+	usecase *usecase.CategoryUsecase
+}
+
+func NewCategoryController(usecase *usecase.CategoryUsecase) *CategoryController {
+	return &CategoryController{usecase: usecase}
+}
+
+// ListCategories serves GET /categories : the category tree
+func (c *CategoryController) ListCategories(w http.ResponseWriter, r *http.Request) {
+	tree, err := c.usecase.GetTree(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if tree == nil {
+		w.Write([]byte("[]"))
+		return
+	}
+	json.NewEncoder(w).Encode(tree)
+}
+
+// GetCategory serves GET /categories/{id} with the attribute schema for its items, inherited attributes included
+func (c *CategoryController) GetCategory(w http.ResponseWriter, r *http.Request) {
+	category, err := c.usecase.GetCategory(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(category)
+}
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"hackathon-backend/pkg/middleware"
+	"hackathon-backend/usecase"
+	"log"
+	"net/http"
+)
+
+// ErrorResponse is the body of every error reply.
+type ErrorResponse struct {
+	Code    string               `json:"code"`
+	Message string               `json:"message"`
+	Details []usecase.FieldError `json:"details,omitempty"` // Validation: one entry per bad field
+}
+
+// errorStatus maps a usecase error kind to its HTTP status and response code.
+func errorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, usecase.ErrNotFound):
+		return http.StatusNotFound, "not_found"
+	case errors.Is(err, usecase.ErrUnauthorized):
+		return http.StatusUnauthorized, "unauthorized"
+	case errors.Is(err, usecase.ErrForbidden):
+		return http.StatusForbidden, "forbidden"
+	case errors.Is(err, usecase.ErrConflict):
+		return http.StatusConflict, "conflict"
+	case errors.Is(err, usecase.ErrVersionRequired):
+		return http.StatusPreconditionRequired, "precondition_required"
+	case errors.Is(err, usecase.ErrVersionMismatch):
+		return http.StatusPreconditionFailed, "precondition_failed"
+	case errors.Is(err, usecase.ErrValidation):
+		return http.StatusBadRequest, "validation_failed"
+	case errors.Is(err, usecase.ErrUnavailable):
+		return http.StatusServiceUnavailable, "unavailable"
+	case errors.Is(err, context.DeadlineExceeded):
+		// Checked before UpstreamAI: a Gemini call that ran out of time is a timeout
+		return http.StatusGatewayTimeout, "timeout"
+	case errors.Is(err, usecase.ErrUpstreamAI):
+		return http.StatusBadGateway, "upstream_ai"
+	}
+	return http.StatusInternalServerError, "internal"
+}
+
+// writeError sends err as an ErrorResponse. Internal errors are logged and
+// replaced by a generic message so database details never reach clients.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status, code := errorStatus(err)
+	body := ErrorResponse{Code: code, Message: err.Error()}
+	var domainErr *usecase.Error
+	if errors.As(err, &domainErr) {
+		body.Message = domainErr.Message
+		body.Details = domainErr.Fields
+	}
+	if status >= http.StatusInternalServerError {
+		log.Printf("%s %s: %v request_id=%s", r.Method, r.URL.Path, err, middleware.GetRequestID(r.Context()))
+		if domainErr == nil {
+			body.Message = http.StatusText(status)
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// decodeBody reads the JSON request body into v, answering 400 itself if it is malformed.
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, r, &usecase.Error{
+			Kind:    usecase.ErrValidation,
+			Message: "invalid JSON body",
+			Fields:  []usecase.FieldError{{Field: "body", Message: err.Error()}},
+		})
+		return false
+	}
+	return true
+}
@@ -2,8 +2,11 @@ package controller
 
 import (
 	"encoding/json"
+	"fmt"
+	"hackathon-backend/model"
 	"hackathon-backend/usecase"
 	"net/http"
+	"strconv"
 	"strings"
 )
 
@@ -15,305 +18,387 @@ func NewItemController(usecase *usecase.ItemUsecase) *ItemController {
 	return &ItemController{usecase: usecase}
 }
 
-func (c *ItemController) GetItems(w http.ResponseWriter, r *http.Request) {
-	items, err := c.usecase.GetAllItems()
+type CreateItemRequest struct {
+	Name                 string `json:"name"`
+	Price                int    `json:"price"`
+	Description          string `json:"description"`
+	UserID               string `json:"user_id"`
+	AINegotiationEnabled bool   `json:"ai_negotiation_enabled"`
+	MinPrice             *int   `json:"min_price"`
+	ImageURL             string `json:"image_url"`
+	AutoApproveAnswers   bool   `json:"auto_approve_answers"`
+	AutoApproveMinPrice  *int   `json:"auto_approve_min_price"`
+	model.ItemDetails
+	model.ShippingOptions
+}
+
+type BuyRequest struct {
+	UserID    string `json:"user_id"`
+	AddressID string `json:"address_id"` // From the buyer's address book; empty uses their default
+	ShipTo    string `json:"ship_to"`    // Destination prefecture code, only when the address book is off
+}
+
+type SendMessageRequest struct {
+	UserID    string `json:"user_id"`
+	Content   string `json:"content"`
+	BuyerID   string `json:"buyer_id"`    // Thread to post in; required when the seller replies
+	Channel   string `json:"channel"`     // "negotiation" (default) or "public"
+	ReplyToID string `json:"reply_to_id"` // Public channel: the question being answered
+}
+
+// ListItems serves GET /items?category=&brand=&condition=good,fair&attr.<key>=
+// (category includes its subcategories)
+func (c *ItemController) ListItems(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := model.ItemFilter{CategoryID: q.Get("category"), Brand: q.Get("brand")}
+	if conditions := q.Get("condition"); conditions != "" {
+		filter.Conditions = strings.Split(conditions, ",")
+	}
+	for key, values := range q {
+		if attr, ok := strings.CutPrefix(key, "attr."); ok {
+			if filter.Attributes == nil {
+				filter.Attributes = make(map[string]string)
+			}
+			filter.Attributes[attr] = values[0]
+		}
+	}
+	items, err := c.usecase.ListItems(r.Context(), filter)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
-
 	w.Header().Set("Content-Type", "application/json")
 	if items == nil {
 		w.Write([]byte("[]"))
 		return
 	}
+	json.NewEncoder(w).Encode(items)
 }
 
-type CreateItemRequest struct {
-	Name                 string `json:"name"`
-	Price                int    `json:"price"`
-	Description          string `json:"description"`
-	UserID               string `json:"user_id"`
-	AINegotiationEnabled bool   `json:"ai_negotiation_enabled"`
-	MinPrice             *int   `json:"min_price"`
-	ImageURL             string `json:"image_url"`
+// CreateItem serves POST /items
+func (c *ItemController) CreateItem(w http.ResponseWriter, r *http.Request) {
+	var req CreateItemRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	item, err := c.usecase.CreateItem(r.Context(), req.Name, req.Price, req.Description, req.UserID, req.AINegotiationEnabled, req.MinPrice, req.ImageURL, req.AutoApproveAnswers, req.AutoApproveMinPrice, req.ItemDetails, req.ShippingOptions)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(item)
 }
 
-func (c *ItemController) HandleItems(w http.ResponseWriter, r *http.Request) {
-    w.Header().Set("Access-Control-Allow-Origin", "*")
-    w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-    w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+// GetItem serves GET /items/{id}. The ETag is the item version, for If-Match on PUT/PATCH.
+func (c *ItemController) GetItem(w http.ResponseWriter, r *http.Request) {
+	item, err := c.usecase.GetItemByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeItem(w, item)
+}
 
-    if r.Method == "OPTIONS" {
-        w.WriteHeader(http.StatusOK)
-        return
-    }
+// UpdateItem serves PUT /items/{id}: every field is replaced, except that an
+// empty image_url keeps the current image. If-Match is optional here.
+func (c *ItemController) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	var req CreateItemRequest // Reuse structure
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	item, err := c.usecase.UpdateItem(r.Context(), r.PathValue("id"), req.UserID, req.Name, req.Price, req.Description, req.AINegotiationEnabled, req.MinPrice, req.ImageURL, req.AutoApproveAnswers, req.AutoApproveMinPrice, req.ItemDetails, req.ShippingOptions, ifMatchVersion(r))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeItem(w, item)
+}
 
-    switch r.Method {
-    case "GET":
-        items, err := c.usecase.GetAllItems()
-        if err != nil {
-            http.Error(w, err.Error(), http.StatusInternalServerError)
-            return
-        }
-        w.Header().Set("Content-Type", "application/json")
-        if items == nil {
-             w.Write([]byte("[]"))
-             return
-        }
-        json.NewEncoder(w).Encode(items)
-    case "POST":
-        var req CreateItemRequest
-        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-             http.Error(w, err.Error(), http.StatusBadRequest)
-             return
-        }
-        item, err := c.usecase.CreateItem(req.Name, req.Price, req.Description, req.UserID, req.AINegotiationEnabled, req.MinPrice, req.ImageURL)
-        if err != nil {
-             http.Error(w, err.Error(), http.StatusInternalServerError)
-             return
-        }
-        w.Header().Set("Content-Type", "application/json")
-        json.NewEncoder(w).Encode(item)
-    default:
-        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-    }
+type PatchItemRequest struct {
+	model.ItemPatch
+	UserID  string `json:"user_id"`
+	Version int    `json:"version"` // Alternative to If-Match for clients that can't set headers
 }
 
-type BuyRequest struct {
-    UserID string `json:"user_id"`
+// PatchItem serves PATCH /items/{id}: only the fields present are changed. The
+// item version is required, from If-Match or the body; a stale one gets 412.
+func (c *ItemController) PatchItem(w http.ResponseWriter, r *http.Request) {
+	var req PatchItemRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	version := ifMatchVersion(r)
+	if version == 0 {
+		version = req.Version
+	}
+	item, err := c.usecase.PatchItem(r.Context(), r.PathValue("id"), req.UserID, req.ItemPatch, version)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeItem(w, item)
 }
 
-type SendMessageRequest struct {
-    UserID  string `json:"user_id"`
-    Content string `json:"content"`
+// writeItem sends an item with its version as the ETag.
+func writeItem(w http.ResponseWriter, item *model.Item) {
+	w.Header().Set("ETag", fmt.Sprintf(`"%d"`, item.Version))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(item)
 }
 
-func (c *ItemController) HandleItemDetail(w http.ResponseWriter, r *http.Request) {
-    w.Header().Set("Access-Control-Allow-Origin", "*")
-    w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-    w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-    
-    if r.Method == "OPTIONS" {
-        w.WriteHeader(http.StatusOK)
-        return
-    }
+// ifMatchVersion reads the item version from If-Match: 0 when the header is
+// absent or "*", -1 (never current) when it isn't one of our ETags.
+func ifMatchVersion(r *http.Request) int {
+	tag := strings.TrimSpace(r.Header.Get("If-Match"))
+	if tag == "" || tag == "*" {
+		return 0
+	}
+	version, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(tag, "W/"), `"`))
+	if err != nil || version < 1 {
+		return -1
+	}
+	return version
+}
 
-    // path: /items/{id} or /items/{id}/buy or /items/{id}/messages
-    parts := strings.Split(r.URL.Path, "/")
-    if len(parts) < 3 {
-        http.Error(w, "Invalid URL", http.StatusBadRequest)
-        return
-    }
-    id := parts[2]
+// DeleteItem serves DELETE /items/{id}?user_id= (query param, since DELETE bodies are discouraged)
+func (c *ItemController) DeleteItem(w http.ResponseWriter, r *http.Request) {
+	if err := c.usecase.DeleteItem(r.Context(), r.PathValue("id"), r.URL.Query().Get("user_id")); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"status": "deleted"}`))
+}
 
-    // Check if it is a buy request
-    if len(parts) >= 4 && parts[3] == "buy" {
-        if r.Method != "PUT" {
-            http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-            return
-        }
-        var req BuyRequest
-        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-             http.Error(w, err.Error(), http.StatusBadRequest)
-             return
-        }
-        item, err := c.usecase.PurchaseItem(id, req.UserID)
-        if err != nil {
-             if err.Error() == "item not found" {
-                 http.Error(w, err.Error(), http.StatusNotFound)
-                 return
-             }
-             if err.Error() == "item already sold" {
-                 http.Error(w, err.Error(), http.StatusConflict)
-                 return
-             }
-             http.Error(w, err.Error(), http.StatusInternalServerError)
-             return
-        }
-        w.Header().Set("Content-Type", "application/json")
-        json.NewEncoder(w).Encode(item)
-        return
-    }
+// PurchaseItem serves PUT /items/{id}/buy
+func (c *ItemController) PurchaseItem(w http.ResponseWriter, r *http.Request) {
+	var req BuyRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	item, err := c.usecase.PurchaseItem(r.Context(), r.PathValue("id"), req.UserID, req.AddressID, req.ShipTo)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(item)
+}
 
-    // Check if it is a messages request
-    if len(parts) >= 4 && parts[3] == "messages" {
-        // Handle Retry: /items/{id}/messages/retry
-        if len(parts) >= 5 && parts[4] == "retry" {
-            if r.Method == "POST" {
-                 var req struct {
-                     UserID      string `json:"user_id"`
-                     Instruction string `json:"instruction"`
-                 }
-                 if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-                     http.Error(w, err.Error(), http.StatusBadRequest)
-                     return
-                 }
-                 
-                 aiMsg, err := c.usecase.RegenerateAIMessage(id, req.UserID, req.Instruction)
-                 if err != nil {
-                      http.Error(w, err.Error(), http.StatusInternalServerError)
-                      return
-                 }
-                 w.Header().Set("Content-Type", "application/json")
-                 json.NewEncoder(w).Encode(aiMsg)
-                 return
-            } else {
-                 http.Error(w, "Method not allowed for retry", http.StatusMethodNotAllowed)
-                 return
-            }
-        }
+// GetShippingQuote serves GET /items/{id}/shipping-quote?to= : the total a buyer
+// in prefecture "to" pays (to may be omitted when shipping is included)
+func (c *ItemController) GetShippingQuote(w http.ResponseWriter, r *http.Request) {
+	quote, err := c.usecase.GetShippingQuote(r.Context(), r.PathValue("id"), r.URL.Query().Get("to"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(quote)
+}
 
-        if r.Method == "POST" {
-            var req SendMessageRequest
-            if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-                http.Error(w, err.Error(), http.StatusBadRequest)
-                return
-            }
-            userMsg, aiMsg, err := c.usecase.SendMessage(id, req.UserID, req.Content)
-            if err != nil {
-                 http.Error(w, err.Error(), http.StatusInternalServerError)
-                 return
-            }
-            // Return latest message (or both)
-             w.Header().Set("Content-Type", "application/json")
-             response := map[string]interface{}{
-                 "user_message": userMsg,
-                 "ai_message": aiMsg, // Can be nil
-             }
-            json.NewEncoder(w).Encode(response)
-            return
-        } else if r.Method == "GET" {
-             // Extract userID query param for filtering
-             userID := r.URL.Query().Get("user_id")
-             msgs, err := c.usecase.GetMessages(id, userID)
-             if err != nil {
-                 http.Error(w, err.Error(), http.StatusInternalServerError)
-                 return
-             }
-             w.Header().Set("Content-Type", "application/json")
-             if msgs == nil {
-                 w.Write([]byte(`{"messages": []}`))
-                 return
-             }
-             json.NewEncoder(w).Encode(map[string]interface{}{"messages": msgs})
-             return
-        } else {
-             http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-             return
-        }
-    }
+// EstimateShipping serves GET /shipping/estimate?method=&size=&from=&to=
+func (c *ItemController) EstimateShipping(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	rate, err := c.usecase.EstimateShipping(q.Get("method"), q.Get("size"), q.Get("from"), q.Get("to"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rate)
+}
 
-    switch r.Method {
-    case "GET":
-        item, err := c.usecase.GetItemByID(id)
-        if err != nil {
-             http.Error(w, err.Error(), http.StatusInternalServerError)
-             return
-        }
-        if item == nil {
-            http.Error(w, "Item not found", http.StatusNotFound)
-            return
-        }
-        w.Header().Set("Content-Type", "application/json")
-        json.NewEncoder(w).Encode(item)
-    case "DELETE":
-        // Extract user_id from query or header? For MPV, query param or body to verify owner
-        // Since DELETE body is discouraged, let's use Query Param `user_id` or Header `X-User-ID`
-        // Let's use Query Param for simplicity as we used it elsewhere
-        userID := r.URL.Query().Get("user_id")
-        if userID == "" {
-             http.Error(w, "user_id required", http.StatusUnauthorized)
-             return
-        }
-        err := c.usecase.DeleteItem(id, userID)
-        if err != nil {
-             http.Error(w, err.Error(), http.StatusBadRequest)
-             return
-        }
-        w.WriteHeader(http.StatusOK)
-        w.Write([]byte(`{"status": "deleted"}`))
-    case "PUT":
-        // Update Item
-        var req CreateItemRequest // Reuse structure
-        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-             http.Error(w, err.Error(), http.StatusBadRequest)
-             return
-        }
-        item, err := c.usecase.UpdateItem(id, req.UserID, req.Name, req.Price, req.Description, req.AINegotiationEnabled, req.MinPrice, req.ImageURL)
-        if err != nil {
-             status := http.StatusInternalServerError
-             if err.Error() == "unauthorized" {
-                 status = http.StatusUnauthorized
-             } else if err.Error() == "item not found" {
-                 status = http.StatusNotFound
-             }
-             http.Error(w, err.Error(), status)
-             return
-        }
-        w.Header().Set("Content-Type", "application/json")
-        json.NewEncoder(w).Encode(item)
-    default:
-        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-    }
+// GetFAQ serves GET /items/{id}/faq : the public FAQ
+func (c *ItemController) GetFAQ(w http.ResponseWriter, r *http.Request) {
+	faqs, err := c.usecase.GetFAQ(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if faqs == nil {
+		w.Write([]byte(`{"faq": []}`))
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"faq": faqs})
+}
+
+// GetPriceHistory serves GET /items/{id}/price-history
+func (c *ItemController) GetPriceHistory(w http.ResponseWriter, r *http.Request) {
+	history, err := c.usecase.GetPriceHistory(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+// GetThreads serves GET /items/{id}/threads?user_id= : the seller's inbox for one item
+func (c *ItemController) GetThreads(w http.ResponseWriter, r *http.Request) {
+	threads, err := c.usecase.GetThreads(r.Context(), r.PathValue("id"), r.URL.Query().Get("user_id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if threads == nil {
+		w.Write([]byte(`{"threads": []}`))
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"threads": threads})
+}
+
+// GetMessages serves GET /items/{id}/messages?user_id=&buyer_id=&channel=
+// Sellers pick a thread with buyer_id.
+func (c *ItemController) GetMessages(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	msgs, err := c.usecase.GetMessages(r.Context(), r.PathValue("id"), q.Get("user_id"), q.Get("buyer_id"), q.Get("channel"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if msgs == nil {
+		w.Write([]byte(`{"messages": []}`))
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"messages": msgs})
+}
+
+// SendMessage serves POST /items/{id}/messages
+func (c *ItemController) SendMessage(w http.ResponseWriter, r *http.Request) {
+	var req SendMessageRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	userMsg, job, err := c.usecase.SendMessage(r.Context(), r.PathValue("id"), req.UserID, req.Content, req.BuyerID, req.Channel, req.ReplyToID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	// The AI reply is generated in the background; follow it via ai_job or /messages/{id}/job
+	w.Header().Set("Content-Type", "application/json")
+	if job != nil {
+		w.WriteHeader(http.StatusAccepted)
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"user_message": userMsg,
+		"ai_message":   nil, // Kept for older clients; the reply arrives asynchronously
+		"ai_job":       job, // Can be nil
+	})
+}
+
+// MarkThreadRead serves PUT /items/{id}/messages/read
+func (c *ItemController) MarkThreadRead(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserID  string `json:"user_id"`
+		BuyerID string `json:"buyer_id"` // Thread; required for the seller
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if err := c.usecase.MarkThreadRead(r.Context(), r.PathValue("id"), req.UserID, req.BuyerID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"status": "read"}`))
 }
 
-func (c *ItemController) HandleMessages(w http.ResponseWriter, r *http.Request) {
-    w.Header().Set("Access-Control-Allow-Origin", "*")
-    w.Header().Set("Access-Control-Allow-Methods", "POST, PUT, OPTIONS")
-    w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+// RegenerateAIMessage serves POST /items/{id}/messages/retry
+func (c *ItemController) RegenerateAIMessage(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserID      string `json:"user_id"`
+		BuyerID     string `json:"buyer_id"` // Thread to regenerate; defaults to the latest active one
+		Instruction string `json:"instruction"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	aiMsg, err := c.usecase.RegenerateAIMessage(r.Context(), r.PathValue("id"), req.UserID, req.BuyerID, req.Instruction)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(aiMsg)
+}
 
-    if r.Method == "OPTIONS" {
-        w.WriteHeader(http.StatusOK)
-        return
-    }
+// GetMessageJob serves GET /messages/{id}/job?user_id= : status of the AI reply generated for this message
+func (c *ItemController) GetMessageJob(w http.ResponseWriter, r *http.Request) {
+	job, err := c.usecase.GetMessageJob(r.Context(), r.PathValue("id"), r.URL.Query().Get("user_id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
 
-    // path: /messages/{id}/approve
-    parts := strings.Split(r.URL.Path, "/")
-    if len(parts) < 4 {
-        http.Error(w, "Invalid URL", http.StatusBadRequest)
-        return
-    }
-    msgID := parts[2]
-    action := parts[3]
+// ApproveMessage serves PUT /messages/{id}/approve
+func (c *ItemController) ApproveMessage(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserID string `json:"user_id"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if err := c.usecase.ApproveMessage(r.Context(), r.PathValue("id"), req.UserID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"status": "approved"}`))
+}
 
-    if action == "approve" && r.Method == "PUT" {
-        // Need userID to verify permission (conceptually)
-        // For now, we trust the caller has permission (Client side checks + knowing the draft ID)
-        var req struct {
-            UserID string `json:"user_id"`
-        }
-         if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-             http.Error(w, err.Error(), http.StatusBadRequest)
-             return
-         }
+// RejectMessage serves PUT /messages/{id}/reject
+func (c *ItemController) RejectMessage(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserID string `json:"user_id"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if err := c.usecase.RejectMessage(r.Context(), r.PathValue("id"), req.UserID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"status": "rejected"}`))
+}
 
-        err := c.usecase.ApproveMessage(msgID)
-        if err != nil {
-             http.Error(w, err.Error(), http.StatusInternalServerError)
-             return
-        }
-        w.WriteHeader(http.StatusOK)
-        w.Write([]byte(`{"status": "approved"}`))
-        w.WriteHeader(http.StatusOK)
-        w.Write([]byte(`{"status": "approved"}`))
-    } else if action == "reject" && r.Method == "PUT" {
-        var req struct {
-            UserID string `json:"user_id"`
-        }
-         if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-             http.Error(w, err.Error(), http.StatusBadRequest)
-             return
-         }
+// RevokeMessage serves PUT /messages/{id}/revoke : seller override for an
+// auto-approved AI message, back to draft
+func (c *ItemController) RevokeMessage(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserID string `json:"user_id"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if err := c.usecase.RevokeMessage(r.Context(), r.PathValue("id"), req.UserID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"status": "revoked"}`))
+}
 
-        err := c.usecase.RejectMessage(msgID, req.UserID)
-        if err != nil {
-             http.Error(w, err.Error(), http.StatusInternalServerError)
-             return
-        }
-        w.WriteHeader(http.StatusOK)
-        w.Write([]byte(`{"status": "rejected"}`))
-    } else {
-        http.Error(w, "Not found or method not allowed", http.StatusNotFound)
-    }
+// GetInbox serves GET /threads?user_id= : every thread the user takes part in,
+// with unread and pending-draft counts.
+func (c *ItemController) GetInbox(w http.ResponseWriter, r *http.Request) {
+	threads, err := c.usecase.GetInbox(r.Context(), r.URL.Query().Get("user_id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if threads == nil {
+		w.Write([]byte(`{"threads": []}`))
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"threads": threads})
 }
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"encoding/json"
+	"hackathon-backend/usecase"
+	"net/http"
+)
+
+type NegotiationController struct {
+	usecase *usecase.NegotiationUsecase
+}
+
+func NewNegotiationController(usecase *usecase.NegotiationUsecase) *NegotiationController {
+	return &NegotiationController{usecase: usecase}
+}
+
+// GetPromptReport serves GET /negotiations/prompt-report
+func (c *NegotiationController) GetPromptReport(w http.ResponseWriter, r *http.Request) {
+	reports, err := c.usecase.GetPromptReport(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if reports == nil {
+		w.Write([]byte(`{"versions": []}`))
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"versions": reports})
+}
+
+// GetSellerStats serves GET /sellers/{id}/negotiations/stats?user_id={requester}
+func (c *NegotiationController) GetSellerStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := c.usecase.GetSellerStats(r.Context(), r.PathValue("id"), r.URL.Query().Get("user_id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
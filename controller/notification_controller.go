@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"encoding/json"
+	"hackathon-backend/model"
+	"hackathon-backend/usecase"
+	"net/http"
+	"strconv"
+)
+
+type NotificationController struct {
+	usecase *usecase.NotificationUsecase
+}
+
+func NewNotificationController(usecase *usecase.NotificationUsecase) *NotificationController {
+	return &NotificationController{usecase: usecase}
+}
+
+// ListNotifications serves GET /notifications?user_id=&unread=true&limit=
+func (c *NotificationController) ListNotifications(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	notifications, unread, err := c.usecase.GetNotifications(r.Context(), q.Get("user_id"), q.Get("unread") == "true", limit)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if notifications == nil {
+		notifications = []model.Notification{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"notifications": notifications, "unread_count": unread})
+}
+
+// MarkRead serves PUT /notifications/{id}/read
+func (c *NotificationController) MarkRead(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserID string `json:"user_id"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if err := c.usecase.MarkRead(r.Context(), r.PathValue("id"), req.UserID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"status": "read"}`))
+}
+
+// MarkAllRead serves PUT /notifications/read-all
+func (c *NotificationController) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserID string `json:"user_id"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if err := c.usecase.MarkAllRead(r.Context(), req.UserID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"status": "read"}`))
+}
+
+// GetPreferences serves GET /notification-preferences?user_id=
+func (c *NotificationController) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	prefs, err := c.usecase.GetPreferences(r.Context(), r.URL.Query().Get("user_id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"preferences": prefs})
+}
+
+// UpdatePreferences serves PUT /notification-preferences with
+// {"user_id", "preferences": [{"event_type", "channel", "enabled"}]}
+func (c *NotificationController) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserID      string                         `json:"user_id"`
+		Preferences []model.NotificationPreference `json:"preferences"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	prefs, err := c.usecase.UpdatePreferences(r.Context(), req.UserID, req.Preferences)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"preferences": prefs})
+}
+
+// GetVAPIDPublicKey serves GET /push-subscriptions : the key browsers subscribe with
+func (c *NotificationController) GetVAPIDPublicKey(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"vapid_public_key": c.usecase.VAPIDPublicKey()})
+}
+
+// pushSubscriptionRequest is a browser PushSubscription as produced by subscription.toJSON()
+type pushSubscriptionRequest struct {
+	UserID   string `json:"user_id"`
+	Endpoint string `json:"endpoint"`
+	Keys     struct {
+		P256dh string `json:"p256dh"`
+		Auth   string `json:"auth"`
+	} `json:"keys"`
+}
+
+// Subscribe serves POST /push-subscriptions
+func (c *NotificationController) Subscribe(w http.ResponseWriter, r *http.Request) {
+	var req pushSubscriptionRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if err := c.usecase.Subscribe(r.Context(), req.UserID, req.Endpoint, req.Keys.P256dh, req.Keys.Auth); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"status": "subscribed"}`))
+}
+
+// Unsubscribe serves DELETE /push-subscriptions with {"user_id", "endpoint"}
+func (c *NotificationController) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	var req pushSubscriptionRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if err := c.usecase.Unsubscribe(r.Context(), req.UserID, req.Endpoint); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"status": "unsubscribed"}`))
+}
@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"hackathon-backend/model"
+	"hackathon-backend/usecase"
+	"net/http"
+)
+
+type OrderCaseController struct {
+	usecase *usecase.OrderCaseUsecase
+}
+
+func NewOrderCaseController(usecase *usecase.OrderCaseUsecase) *OrderCaseController {
+	return &OrderCaseController{usecase: usecase}
+}
+
+type OpenCaseRequest struct {
+	UserID  string `json:"user_id"`
+	Reason  string `json:"reason"`
+	Details string `json:"details"`
+}
+
+type CaseActionRequest struct {
+	UserID string `json:"user_id"`
+}
+
+type CaseMessageRequest struct {
+	UserID      string `json:"user_id"`
+	Content     string `json:"content"`
+	EvidenceURL string `json:"evidence_url"` // Optional photo or document
+}
+
+type ResolveCaseRequest struct {
+	UserID  string `json:"user_id"`
+	Outcome string `json:"outcome"` // refund, complete or relist
+	Note    string `json:"note"`
+}
+
+// RequestCancellation serves POST /items/{id}/cancellation
+func (c *OrderCaseController) RequestCancellation(w http.ResponseWriter, r *http.Request) {
+	c.openCase(w, r, c.usecase.RequestCancellation)
+}
+
+// OpenDispute serves POST /items/{id}/disputes
+func (c *OrderCaseController) OpenDispute(w http.ResponseWriter, r *http.Request) {
+	c.openCase(w, r, c.usecase.OpenDispute)
+}
+
+func (c *OrderCaseController) openCase(w http.ResponseWriter, r *http.Request, open func(ctx context.Context, itemID string, userID string, reason string, details string) (*model.OrderCase, error)) {
+	var req OpenCaseRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	orderCase, err := open(r.Context(), r.PathValue("id"), req.UserID, req.Reason, req.Details)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(orderCase)
+}
+
+// ListItemCases serves GET /items/{id}/cases?user_id=
+func (c *OrderCaseController) ListItemCases(w http.ResponseWriter, r *http.Request) {
+	cases, err := c.usecase.GetItemCases(r.Context(), r.PathValue("id"), r.URL.Query().Get("user_id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if cases == nil {
+		cases = []model.OrderCase{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cases)
+}
+
+// GetCase serves GET /cases/{id}?user_id= : the case with its messages
+func (c *OrderCaseController) GetCase(w http.ResponseWriter, r *http.Request) {
+	orderCase, err := c.usecase.GetCase(r.Context(), r.PathValue("id"), r.URL.Query().Get("user_id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(orderCase)
+}
+
+// AddMessage serves POST /cases/{id}/messages
+func (c *OrderCaseController) AddMessage(w http.ResponseWriter, r *http.Request) {
+	var req CaseMessageRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	msg, err := c.usecase.AddMessage(r.Context(), r.PathValue("id"), req.UserID, req.Content, req.EvidenceURL)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(msg)
+}
+
+// Accept serves PUT /cases/{id}/accept
+func (c *OrderCaseController) Accept(w http.ResponseWriter, r *http.Request) {
+	c.closeCase(w, r, c.usecase.AcceptCancellation)
+}
+
+// Decline serves PUT /cases/{id}/decline
+func (c *OrderCaseController) Decline(w http.ResponseWriter, r *http.Request) {
+	c.closeCase(w, r, c.usecase.DeclineCancellation)
+}
+
+// Withdraw serves PUT /cases/{id}/withdraw
+func (c *OrderCaseController) Withdraw(w http.ResponseWriter, r *http.Request) {
+	c.closeCase(w, r, c.usecase.Withdraw)
+}
+
+func (c *OrderCaseController) closeCase(w http.ResponseWriter, r *http.Request, close func(ctx context.Context, caseID string, userID string) (*model.OrderCase, error)) {
+	var req CaseActionRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	orderCase, err := close(r.Context(), r.PathValue("id"), req.UserID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(orderCase)
+}
+
+// Resolve serves PUT /cases/{id}/resolve (admins)
+func (c *OrderCaseController) Resolve(w http.ResponseWriter, r *http.Request) {
+	var req ResolveCaseRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	orderCase, err := c.usecase.Resolve(r.Context(), r.PathValue("id"), req.UserID, req.Outcome, req.Note)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(orderCase)
+}
+
+// ListCases serves GET /admin/cases?user_id=&kind=&status= : the admin queue, open disputes by default
+func (c *OrderCaseController) ListCases(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	cases, err := c.usecase.ListCases(r.Context(), q.Get("user_id"), q.Get("kind"), q.Get("status"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if cases == nil {
+		cases = []model.OrderCase{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cases)
+}
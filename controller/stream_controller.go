@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"hackathon-backend/pkg/sse"
+	"hackathon-backend/usecase"
+	"net/http"
+	"time"
+)
+
+const streamHeartbeat = 25 * time.Second
+
+type StreamController struct {
+	usecase *usecase.StreamUsecase
+}
+
+func NewStreamController(usecase *usecase.StreamUsecase) *StreamController {
+	return &StreamController{usecase: usecase}
+}
+
+// Stream serves GET /events/stream?user_id= as a server-sent event stream
+// of the user's live marketplace activity (new messages, drafts, sales).
+func (c *StreamController) Stream(w http.ResponseWriter, r *http.Request) {
+	events, cancel, err := c.usecase.Subscribe(r.URL.Query().Get("user_id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	defer cancel()
+	sse.Serve(w, r, events, streamHeartbeat)
+}
@@ -20,31 +20,16 @@ type RegisterRequest struct {
 	Email string `json:"email"`
 }
 
+// Register serves POST /register
 func (c *UserController) Register(w http.ResponseWriter, r *http.Request) {
-    // CORS headers
-    w.Header().Set("Access-Control-Allow-Origin", "*")
-    w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
-    w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-
-    if r.Method == "OPTIONS" {
-        w.WriteHeader(http.StatusOK)
-        return
-    }
-
-	if r.Method != "POST" {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	var req RegisterRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if !decodeBody(w, r, &req) {
 		return
 	}
 
-	user, err := c.usecase.RegisterUser(req.ID, req.Name, req.Email)
+	user, err := c.usecase.RegisterUser(r.Context(), req.ID, req.Name, req.Email)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
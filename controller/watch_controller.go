@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"encoding/json"
+	"hackathon-backend/model"
+	"hackathon-backend/usecase"
+	"net/http"
+)
+
+type WatchController struct {
+	usecase *usecase.WatchUsecase
+}
+
+func NewWatchController(usecase *usecase.WatchUsecase) *WatchController {
+	return &WatchController{usecase: usecase}
+}
+
+type WatchRequest struct {
+	UserID      string `json:"user_id"`
+	TargetPrice *int   `json:"target_price"` // Omit to be alerted on every drop
+}
+
+// Watch serves PUT /items/{id}/watch
+func (c *WatchController) Watch(w http.ResponseWriter, r *http.Request) {
+	var req WatchRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	watch, err := c.usecase.Watch(r.Context(), r.PathValue("id"), req.UserID, req.TargetPrice)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(watch)
+}
+
+// Unwatch serves DELETE /items/{id}/watch?user_id=
+func (c *WatchController) Unwatch(w http.ResponseWriter, r *http.Request) {
+	if err := c.usecase.Unwatch(r.Context(), r.PathValue("id"), r.URL.Query().Get("user_id")); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"status": "unwatched"}`))
+}
+
+// Like serves PUT /items/{id}/like
+func (c *WatchController) Like(w http.ResponseWriter, r *http.Request) {
+	var req BuyRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	watch, err := c.usecase.Like(r.Context(), r.PathValue("id"), req.UserID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(watch)
+}
+
+// Unlike serves DELETE /items/{id}/like?user_id=
+func (c *WatchController) Unlike(w http.ResponseWriter, r *http.Request) {
+	if err := c.usecase.Unlike(r.Context(), r.PathValue("id"), r.URL.Query().Get("user_id")); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"status": "unliked"}`))
+}
+
+// ListWatches serves GET /watches?user_id= : the user's watched and liked items
+func (c *WatchController) ListWatches(w http.ResponseWriter, r *http.Request) {
+	watches, err := c.usecase.GetWatches(r.Context(), r.URL.Query().Get("user_id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if watches == nil {
+		watches = []model.ItemWatch{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(watches)
+}
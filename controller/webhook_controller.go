@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"encoding/json"
+	"hackathon-backend/model"
+	"hackathon-backend/usecase"
+	"net/http"
+)
+
+type WebhookController struct {
+	usecase *usecase.WebhookUsecase
+}
+
+func NewWebhookController(usecase *usecase.WebhookUsecase) *WebhookController {
+	return &WebhookController{usecase: usecase}
+}
+
+type RegisterWebhookRequest struct {
+	UserID     string   `json:"user_id"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"` // Empty subscribes to every event
+}
+
+// ListWebhooks serves GET /webhooks?user_id=
+func (c *WebhookController) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	endpoints, err := c.usecase.GetEndpoints(r.Context(), r.URL.Query().Get("user_id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if endpoints == nil {
+		endpoints = []model.WebhookEndpoint{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"webhooks": endpoints})
+}
+
+// RegisterWebhook serves POST /webhooks
+func (c *WebhookController) RegisterWebhook(w http.ResponseWriter, r *http.Request) {
+	var req RegisterWebhookRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	endpoint, err := c.usecase.RegisterEndpoint(r.Context(), req.UserID, req.URL, req.EventTypes)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(endpoint)
+}
+
+// DeleteWebhook serves DELETE /webhooks/{id}
+func (c *WebhookController) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserID string `json:"user_id"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if err := c.usecase.DeleteEndpoint(r.Context(), r.PathValue("id"), req.UserID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"status": "deleted"}`))
+}
+
+// ListDeliveries serves GET /webhooks/{id}/deliveries?user_id=
+func (c *WebhookController) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	deliveries, err := c.usecase.GetDeliveries(r.Context(), r.PathValue("id"), r.URL.Query().Get("user_id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if deliveries == nil {
+		deliveries = []model.WebhookDelivery{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"deliveries": deliveries})
+}
+
+// Redeliver serves POST /webhooks/{id}/deliveries/{delivery_id}/redeliver
+func (c *WebhookController) Redeliver(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserID string `json:"user_id"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if err := c.usecase.RedeliverDelivery(r.Context(), r.PathValue("id"), r.PathValue("delivery_id"), req.UserID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"status": "queued"}`))
+}
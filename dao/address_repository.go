@@ -0,0 +1,121 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"hackathon-backend/model"
+)
+
+type AddressRepository struct {
+	db DBTX
+}
+
+func NewAddressRepository(db *sql.DB) *AddressRepository {
+	return &AddressRepository{db: db}
+}
+
+func (r *AddressRepository) Insert(ctx context.Context, a *model.SealedAddress) error {
+	query := `INSERT INTO user_addresses (id, user_id, prefecture, sealed_fields, is_default, created_at) VALUES (?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, a.ID, a.UserID, a.Prefecture, a.SealedFields, a.IsDefault, a.CreatedAt)
+	return err
+}
+
+// Update replaces the address, reporting false if the user has no such address.
+func (r *AddressRepository) Update(ctx context.Context, a *model.SealedAddress) (bool, error) {
+	query := `UPDATE user_addresses SET prefecture = ?, sealed_fields = ? WHERE id = ? AND user_id = ?`
+	res, err := r.db.ExecContext(ctx, query, a.Prefecture, a.SealedFields, a.ID, a.UserID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// LockUser locks the user's row until the transaction ends, so concurrent
+// changes to one address book run one at a time.
+func (r *AddressRepository) LockUser(ctx context.Context, userID string) error {
+	var id string
+	err := r.db.QueryRowContext(ctx, `SELECT id FROM users WHERE id = ? FOR UPDATE`, userID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	return err
+}
+
+// SetDefault makes the address the user's only default.
+func (r *AddressRepository) SetDefault(ctx context.Context, userID string, id string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE user_addresses SET is_default = (id = ?) WHERE user_id = ?`, id, userID)
+	return err
+}
+
+// Delete reports false if the user has no such address.
+func (r *AddressRepository) Delete(ctx context.Context, userID string, id string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM user_addresses WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// GetByID returns nil if the user has no such address.
+func (r *AddressRepository) GetByID(ctx context.Context, userID string, id string) (*model.SealedAddress, error) {
+	query := `SELECT id, user_id, prefecture, sealed_fields, is_default, created_at FROM user_addresses WHERE id = ? AND user_id = ?`
+	var a model.SealedAddress
+	err := r.db.QueryRowContext(ctx, query, id, userID).Scan(&a.ID, &a.UserID, &a.Prefecture, &a.SealedFields, &a.IsDefault, &a.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// GetByUserID lists the user's addresses, the default first.
+func (r *AddressRepository) GetByUserID(ctx context.Context, userID string) ([]model.SealedAddress, error) {
+	query := `SELECT id, user_id, prefecture, sealed_fields, is_default, created_at FROM user_addresses WHERE user_id = ? ORDER BY is_default DESC, created_at ASC`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var addresses []model.SealedAddress
+	for rows.Next() {
+		var a model.SealedAddress
+		if err := rows.Scan(&a.ID, &a.UserID, &a.Prefecture, &a.SealedFields, &a.IsDefault, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, a)
+	}
+	return addresses, rows.Err()
+}
+
+// InsertPurchase stores the address chosen for an item's purchase. a.ID is the
+// address book entry it came from.
+func (r *AddressRepository) InsertPurchase(ctx context.Context, itemID string, a *model.SealedAddress) error {
+	query := `INSERT INTO purchase_addresses (item_id, buyer_id, address_id, prefecture, sealed_fields, created_at) VALUES (?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, itemID, a.UserID, a.ID, a.Prefecture, a.SealedFields, a.CreatedAt)
+	return err
+}
+
+// DeletePurchase drops the address copy of a purchase that was called off.
+func (r *AddressRepository) DeletePurchase(ctx context.Context, itemID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM purchase_addresses WHERE item_id = ?`, itemID)
+	return err
+}
+
+// GetPurchase returns nil if the item was bought without an address.
+func (r *AddressRepository) GetPurchase(ctx context.Context, itemID string) (*model.SealedAddress, error) {
+	query := `SELECT COALESCE(address_id, ''), buyer_id, prefecture, sealed_fields, created_at FROM purchase_addresses WHERE item_id = ?`
+	var a model.SealedAddress
+	err := r.db.QueryRowContext(ctx, query, itemID).Scan(&a.ID, &a.UserID, &a.Prefecture, &a.SealedFields, &a.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
@@ -0,0 +1,102 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"hackathon-backend/model"
+	"time"
+)
+
+type AIJobRepository struct {
+	db DBTX
+}
+
+func NewAIJobRepository(db *sql.DB) *AIJobRepository {
+	return &AIJobRepository{db: db}
+}
+
+const aiJobColumns = `id, kind, item_id, buyer_id, message_id, status, attempts, max_attempts, next_attempt_at, last_error, result_message_id, created_at, updated_at`
+
+func scanAIJob(row rowScanner) (*model.AIJob, error) {
+	var j model.AIJob
+	var buyerID, lastError, resultMessageID sql.NullString
+	if err := row.Scan(&j.ID, &j.Kind, &j.ItemID, &buyerID, &j.MessageID, &j.Status, &j.Attempts, &j.MaxAttempts, &j.NextAttemptAt, &lastError, &resultMessageID, &j.CreatedAt, &j.UpdatedAt); err != nil {
+		return nil, err
+	}
+	j.BuyerID = buyerID.String
+	j.LastError = lastError.String
+	j.ResultMessageID = resultMessageID.String
+	return &j, nil
+}
+
+func (r *AIJobRepository) Create(ctx context.Context, j *model.AIJob) error {
+	query := `INSERT INTO ai_jobs (id, kind, item_id, buyer_id, message_id, status, attempts, max_attempts, next_attempt_at, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, j.ID, j.Kind, j.ItemID, nullIfEmpty(j.BuyerID), j.MessageID, j.Status, j.Attempts, j.MaxAttempts, j.NextAttemptAt, j.CreatedAt, j.UpdatedAt)
+	return err
+}
+
+func (r *AIJobRepository) GetByMessageID(ctx context.Context, messageID string) (*model.AIJob, error) {
+	query := `SELECT ` + aiJobColumns + ` FROM ai_jobs WHERE message_id = ? ORDER BY created_at DESC LIMIT 1`
+	j, err := scanAIJob(r.db.QueryRowContext(ctx, query, messageID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return j, err
+}
+
+// Claim picks jobs that are due (queued, or running with an expired lease after
+// a worker died), marks them running until leaseUntil and counts the attempt.
+func (r *AIJobRepository) Claim(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]model.AIJob, error) {
+	query := `SELECT ` + aiJobColumns + ` FROM ai_jobs
+        WHERE status IN (?, ?) AND next_attempt_at <= ?
+        ORDER BY next_attempt_at ASC, id ASC
+        LIMIT ?`
+	rows, err := r.db.QueryContext(ctx, query, model.JobQueued, model.JobRunning, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	var due []model.AIJob
+	for rows.Next() {
+		j, err := scanAIJob(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		due = append(due, *j)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var claimed []model.AIJob
+	for _, j := range due {
+		res, err := r.db.ExecContext(ctx, `UPDATE ai_jobs SET status = ?, attempts = attempts + 1, next_attempt_at = ?, updated_at = ? WHERE id = ? AND status = ? AND next_attempt_at = ?`,
+			model.JobRunning, leaseUntil, now, j.ID, j.Status, j.NextAttemptAt)
+		if err != nil {
+			return nil, err
+		}
+		if n, _ := res.RowsAffected(); n == 1 {
+			j.Status = model.JobRunning
+			j.Attempts++
+			j.NextAttemptAt = leaseUntil
+			claimed = append(claimed, j)
+		}
+	}
+	return claimed, nil
+}
+
+// Finish records a final status; call it on the transaction's repository
+// together with the job's output so both commit or neither does.
+func (r *AIJobRepository) Finish(ctx context.Context, id string, status string, resultMessageID string, note string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE ai_jobs SET status = ?, result_message_id = ?, last_error = ?, updated_at = ? WHERE id = ?`,
+		status, nullIfEmpty(resultMessageID), nullIfEmpty(note), time.Now(), id)
+	return err
+}
+
+// Retry puts a failed attempt back in the queue.
+func (r *AIJobRepository) Retry(ctx context.Context, id string, nextAttemptAt time.Time, lastError string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE ai_jobs SET status = ?, next_attempt_at = ?, last_error = ?, updated_at = ? WHERE id = ?`,
+		model.JobQueued, nextAttemptAt, lastError, time.Now(), id)
+	return err
+}
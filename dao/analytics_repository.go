@@ -0,0 +1,42 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"hackathon-backend/model"
+	"time"
+)
+
+type AnalyticsRepository struct {
+	db *sql.DB
+}
+
+func NewAnalyticsRepository(db *sql.DB) *AnalyticsRepository {
+	return &AnalyticsRepository{db: db}
+}
+
+func (r *AnalyticsRepository) IncrementDaily(ctx context.Context, day time.Time, eventType string) error {
+	query := `INSERT INTO analytics_daily_events (day, event_type, count) VALUES (?, ?, 1)
+        ON DUPLICATE KEY UPDATE count = count + 1`
+	_, err := r.db.ExecContext(ctx, query, day.Format("2006-01-02"), eventType)
+	return err
+}
+
+func (r *AnalyticsRepository) GetDailyCounts(ctx context.Context, since time.Time) ([]model.DailyEventCount, error) {
+	query := `SELECT DATE_FORMAT(day, '%Y-%m-%d'), event_type, count FROM analytics_daily_events WHERE day >= ? ORDER BY day ASC, event_type ASC`
+	rows, err := r.db.QueryContext(ctx, query, since.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []model.DailyEventCount
+	for rows.Next() {
+		var c model.DailyEventCount
+		if err := rows.Scan(&c.Day, &c.EventType, &c.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
@@ -0,0 +1,46 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"hackathon-backend/model"
+)
+
+type CategoryRepository struct {
+	db DBTX
+}
+
+func NewCategoryRepository(db *sql.DB) *CategoryRepository {
+	return &CategoryRepository{db: db}
+}
+
+// GetAll returns every category, flat, in display order.
+func (r *CategoryRepository) GetAll(ctx context.Context) ([]model.Category, error) {
+	query := `SELECT id, parent_id, name, attribute_schema, sort_order FROM categories ORDER BY sort_order ASC, id ASC`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []model.Category
+	for rows.Next() {
+		var c model.Category
+		var parentID sql.NullString
+		var schema []byte
+		if err := rows.Scan(&c.ID, &parentID, &c.Name, &schema, &c.SortOrder); err != nil {
+			return nil, err
+		}
+		if parentID.Valid {
+			c.ParentID = &parentID.String
+		}
+		if len(schema) > 0 {
+			if err := json.Unmarshal(schema, &c.Attributes); err != nil {
+				return nil, err
+			}
+		}
+		categories = append(categories, c)
+	}
+	return categories, rows.Err()
+}
@@ -0,0 +1,25 @@
+package dao
+
+import (
+	"database/sql"
+	"hackathon-backend/config"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// OpenDB connects to MySQL with the configured pool limits and checks the connection.
+func OpenDB(cfg config.DBConfig) (*sql.DB, error) {
+	db, err := sql.Open("mysql", cfg.DSN())
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
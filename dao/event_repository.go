@@ -0,0 +1,106 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"hackathon-backend/model"
+	"time"
+)
+
+// EventRepository is the domain event outbox.
+type EventRepository struct {
+	db DBTX
+}
+
+func NewEventRepository(db *sql.DB) *EventRepository {
+	return &EventRepository{db: db}
+}
+
+// Append writes events to the outbox; call it on the transaction's repository.
+func (r *EventRepository) Append(ctx context.Context, events ...*model.DomainEvent) error {
+	query := `INSERT INTO domain_events (id, type, aggregate_id, payload, attempts, next_attempt_at, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	for _, e := range events {
+		if _, err := r.db.ExecContext(ctx, query, e.ID, e.Type, e.AggregateID, string(e.Payload), e.Attempts, e.NextAttemptAt, e.CreatedAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClaimDue picks undispatched events whose (retry) time has come, oldest first,
+// and leases them until leaseUntil.
+func (r *EventRepository) ClaimDue(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]model.DomainEvent, error) {
+	query := `
+        SELECT id, type, aggregate_id, payload, attempts, next_attempt_at, created_at
+        FROM domain_events
+        WHERE dispatched_at IS NULL AND next_attempt_at <= ?
+        ORDER BY created_at ASC, id ASC
+        LIMIT ?`
+	rows, err := r.db.QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	var due []model.DomainEvent
+	for rows.Next() {
+		var e model.DomainEvent
+		var payload string
+		if err := rows.Scan(&e.ID, &e.Type, &e.AggregateID, &payload, &e.Attempts, &e.NextAttemptAt, &e.CreatedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		e.Payload = []byte(payload)
+		due = append(due, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var claimed []model.DomainEvent
+	for _, e := range due {
+		res, err := r.db.ExecContext(ctx, `UPDATE domain_events SET next_attempt_at = ? WHERE id = ? AND dispatched_at IS NULL AND next_attempt_at = ?`,
+			leaseUntil, e.ID, e.NextAttemptAt)
+		if err != nil {
+			return nil, err
+		}
+		if n, _ := res.RowsAffected(); n == 1 {
+			claimed = append(claimed, e)
+		}
+	}
+	return claimed, nil
+}
+
+// HandledBy lists the subscribers that already processed an event, so a retry skips them.
+func (r *EventRepository) HandledBy(ctx context.Context, eventID string) (map[string]bool, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT subscriber FROM domain_event_consumers WHERE event_id = ?", eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	handled := make(map[string]bool)
+	for rows.Next() {
+		var subscriber string
+		if err := rows.Scan(&subscriber); err != nil {
+			return nil, err
+		}
+		handled[subscriber] = true
+	}
+	return handled, rows.Err()
+}
+
+func (r *EventRepository) MarkHandled(ctx context.Context, eventID string, subscriber string) error {
+	_, err := r.db.ExecContext(ctx, "INSERT IGNORE INTO domain_event_consumers (event_id, subscriber, handled_at) VALUES (?, ?, ?)", eventID, subscriber, time.Now())
+	return err
+}
+
+func (r *EventRepository) MarkDispatched(ctx context.Context, eventID string, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE domain_events SET dispatched_at = ?, last_error = NULL WHERE id = ?", at, eventID)
+	return err
+}
+
+// MarkFailed schedules another attempt after a subscriber failed.
+func (r *EventRepository) MarkFailed(ctx context.Context, eventID string, attempts int, nextAttemptAt time.Time, lastError string) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE domain_events SET attempts = ?, next_attempt_at = ?, last_error = ? WHERE id = ?", attempts, nextAttemptAt, lastError, eventID)
+	return err
+}
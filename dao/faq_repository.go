@@ -0,0 +1,45 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"hackathon-backend/model"
+)
+
+type FAQRepository struct {
+	db DBTX
+}
+
+func NewFAQRepository(db *sql.DB) *FAQRepository {
+	return &FAQRepository{db: db}
+}
+
+func (r *FAQRepository) Create(ctx context.Context, faq *model.ItemFAQ) error {
+	query := `INSERT INTO item_faqs (id, item_id, question_message_id, answer_message_id, question, answer, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, faq.ID, faq.ItemID, faq.QuestionMessageID, faq.AnswerMessageID, faq.Question, faq.Answer, faq.CreatedAt)
+	return err
+}
+
+func (r *FAQRepository) GetByItemID(ctx context.Context, itemID string) ([]model.ItemFAQ, error) {
+	query := `SELECT id, item_id, question_message_id, answer_message_id, question, answer, created_at FROM item_faqs WHERE item_id = ? ORDER BY created_at ASC, id ASC`
+	rows, err := r.db.QueryContext(ctx, query, itemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var faqs []model.ItemFAQ
+	for rows.Next() {
+		var f model.ItemFAQ
+		if err := rows.Scan(&f.ID, &f.ItemID, &f.QuestionMessageID, &f.AnswerMessageID, &f.Question, &f.Answer, &f.CreatedAt); err != nil {
+			return nil, err
+		}
+		faqs = append(faqs, f)
+	}
+	return faqs, rows.Err()
+}
+
+func (r *FAQRepository) DeleteByAnswerMessageID(ctx context.Context, messageID string) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM item_faqs WHERE answer_message_id = ?", messageID)
+	return err
+}
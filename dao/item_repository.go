@@ -1,28 +1,54 @@
 package dao
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"hackathon-backend/model"
+	"strings"
 )
 
 type ItemRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
 func NewItemRepository(db *sql.DB) *ItemRepository {
 	return &ItemRepository{db: db}
 }
 
-func (r *ItemRepository) GetAll() ([]model.Item, error) {
+// List returns the items that are not deleted, newest first, narrowed by filter.
+func (r *ItemRepository) List(ctx context.Context, filter model.ItemFilter) ([]model.Item, error) {
 	// 修正: buyer_idとstatus, image_urlも取得するように変更
 	// schema.sql: id, name, price, description, user_id, buyer_id, status, image_url, initial_price
 	query := `
-		SELECT id, name, price, description, user_id, buyer_id, status, image_url, initial_price
+		SELECT id, name, price, description, user_id, buyer_id, status, image_url, initial_price, version, category_id, brand, item_condition, attributes, shipping_method, shipping_payer, ship_from, days_to_ship, size_class, shipping_fee
 		FROM items 
-		WHERE status != 'deleted'
-		ORDER BY created_at DESC
-	`
-	rows, err := r.db.Query(query)
+		WHERE status NOT IN ('deleted', 'cancelled')`
+	var args []interface{}
+	if len(filter.CategoryIDs) > 0 {
+		query += " AND category_id IN (?" + strings.Repeat(", ?", len(filter.CategoryIDs)-1) + ")"
+		for _, id := range filter.CategoryIDs {
+			args = append(args, id)
+		}
+	}
+	if filter.Brand != "" {
+		query += " AND brand = ?"
+		args = append(args, filter.Brand)
+	}
+	if len(filter.Conditions) > 0 {
+		query += " AND item_condition IN (?" + strings.Repeat(", ?", len(filter.Conditions)-1) + ")"
+		for _, c := range filter.Conditions {
+			args = append(args, c)
+		}
+	}
+	for key, value := range filter.Attributes {
+		// Keys are checked against the category schema, but go in as a bound JSON path anyway
+		query += " AND JSON_UNQUOTE(JSON_EXTRACT(attributes, ?)) = ?"
+		args = append(args, `$."`+key+`"`, value)
+	}
+	query += " ORDER BY created_at DESC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -33,8 +59,10 @@ func (r *ItemRepository) GetAll() ([]model.Item, error) {
 		var item model.Item
 		var buyerID sql.NullString
 		var imageURL sql.NullString
+		var details itemDetailsColumns
+		var shipping itemShippingColumns
 		
-		if err := rows.Scan(&item.ID, &item.Name, &item.Price, &item.Description, &item.UserID, &buyerID, &item.Status, &imageURL, &item.InitialPrice); err != nil {
+		if err := rows.Scan(&item.ID, &item.Name, &item.Price, &item.Description, &item.UserID, &buyerID, &item.Status, &imageURL, &item.InitialPrice, &item.Version, &details.categoryID, &details.brand, &details.condition, &details.attributes, &shipping.method, &shipping.payer, &shipping.from, &shipping.daysToShip, &shipping.sizeClass, &shipping.fee); err != nil {
 			return nil, err
 		}
 		
@@ -44,6 +72,10 @@ func (r *ItemRepository) GetAll() ([]model.Item, error) {
 		if imageURL.Valid {
 			item.ImageURL = imageURL.String
 		}
+		if item.ItemDetails, err = details.decode(); err != nil {
+			return nil, err
+		}
+		shipping.decode(&item)
 		
 		items = append(items, item)
 	}
@@ -55,26 +87,40 @@ func (r *ItemRepository) GetAll() ([]model.Item, error) {
 	return items, nil
 }
 
-func (r *ItemRepository) IncrementViewCount(id string) error {
-	_, err := r.db.Exec("UPDATE items SET views_count = views_count + 1 WHERE id = ?", id)
+func (r *ItemRepository) IncrementViewCount(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE items SET views_count = views_count + 1 WHERE id = ?", id)
 	return err
 }
 
-func (r *ItemRepository) GetByID(id string) (*model.Item, error) {
+func (r *ItemRepository) GetByID(ctx context.Context, id string) (*model.Item, error) {
+	return r.getByID(ctx, id, "")
+}
+
+// GetByIDForUpdate locks the item row until the surrounding transaction ends.
+func (r *ItemRepository) GetByIDForUpdate(ctx context.Context, id string) (*model.Item, error) {
+	return r.getByID(ctx, id, " FOR UPDATE")
+}
+
+func (r *ItemRepository) getByID(ctx context.Context, id string, lock string) (*model.Item, error) {
 	// Select with new columns
 	query := `
-		SELECT id, name, price, description, user_id, buyer_id, status, views_count, ai_negotiation_enabled, min_price, created_at, image_url, initial_price 
+		SELECT id, name, price, description, user_id, buyer_id, status, views_count, ai_negotiation_enabled, min_price, created_at, image_url, initial_price, auto_approve_answers, auto_approve_min_price, prompt_version, sold_at, version, category_id, brand, item_condition, attributes, shipping_method, shipping_payer, ship_from, days_to_ship, size_class, shipping_fee
 		FROM items 
 		WHERE id = ?
-	`
-	row := r.db.QueryRow(query, id)
+	` + lock
+	row := r.db.QueryRowContext(ctx, query, id)
 	
 	var item model.Item
 	var buyerID sql.NullString
 	var minPrice sql.NullInt64
 	var imageURL sql.NullString
+	var autoApproveMinPrice sql.NullInt64
+	var promptVersion sql.NullString
+	var soldAt sql.NullTime
+	var details itemDetailsColumns
+	var shipping itemShippingColumns
 	
-	if err := row.Scan(&item.ID, &item.Name, &item.Price, &item.Description, &item.UserID, &buyerID, &item.Status, &item.ViewsCount, &item.AINegotiationEnabled, &minPrice, &item.CreatedAt, &imageURL, &item.InitialPrice); err != nil {
+	if err := row.Scan(&item.ID, &item.Name, &item.Price, &item.Description, &item.UserID, &buyerID, &item.Status, &item.ViewsCount, &item.AINegotiationEnabled, &minPrice, &item.CreatedAt, &imageURL, &item.InitialPrice, &item.AutoApproveAnswers, &autoApproveMinPrice, &promptVersion, &soldAt, &item.Version, &details.categoryID, &details.brand, &details.condition, &details.attributes, &shipping.method, &shipping.payer, &shipping.from, &shipping.daysToShip, &shipping.sizeClass, &shipping.fee); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // Not found
 		}
@@ -91,18 +137,124 @@ func (r *ItemRepository) GetByID(id string) (*model.Item, error) {
 	if imageURL.Valid {
 		item.ImageURL = imageURL.String
 	}
+	if autoApproveMinPrice.Valid {
+		val := int(autoApproveMinPrice.Int64)
+		item.AutoApproveMinPrice = &val
+	}
+	if promptVersion.Valid {
+		item.PromptVersion = &promptVersion.String
+	}
+	if soldAt.Valid {
+		item.SoldAt = &soldAt.Time
+	}
+	var err error
+	if item.ItemDetails, err = details.decode(); err != nil {
+		return nil, err
+	}
+	shipping.decode(&item)
 	
 	return &item, nil
 }
 
-func (r *ItemRepository) Insert(item *model.Item) error {
-	query := `INSERT INTO items (id, name, price, description, user_id, status, ai_negotiation_enabled, min_price, image_url, initial_price) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
-	_, err := r.db.Exec(query, item.ID, item.Name, item.Price, item.Description, item.UserID, item.Status, item.AINegotiationEnabled, item.MinPrice, item.ImageURL, item.InitialPrice)
+func (r *ItemRepository) Insert(ctx context.Context, item *model.Item) error {
+	attributes, err := encodeAttributes(item.Attributes)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO items (id, name, price, description, user_id, status, ai_negotiation_enabled, min_price, image_url, initial_price, auto_approve_answers, auto_approve_min_price, prompt_version, category_id, brand, item_condition, attributes, shipping_method, shipping_payer, ship_from, days_to_ship, size_class) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err = r.db.ExecContext(ctx, query, item.ID, item.Name, item.Price, item.Description, item.UserID, item.Status, item.AINegotiationEnabled, item.MinPrice, item.ImageURL, item.InitialPrice, item.AutoApproveAnswers, item.AutoApproveMinPrice, item.PromptVersion, item.CategoryID, nullString(item.Brand), nullString(item.Condition), attributes,
+		nullString(item.ShippingMethod), nullString(item.ShippingPayer), nullString(item.ShipFrom), nullString(item.DaysToShip), nullString(item.SizeClass))
 	return err
 }
 
-func (r *ItemRepository) Update(item *model.Item) error {
-	query := `UPDATE items SET name=?, price=?, description=?, user_id=?, buyer_id=?, status=?, ai_negotiation_enabled=?, min_price=?, image_url=?, initial_price=? WHERE id=?`
-	_, err := r.db.Exec(query, item.Name, item.Price, item.Description, item.UserID, item.BuyerID, item.Status, item.AINegotiationEnabled, item.MinPrice, item.ImageURL, item.InitialPrice, item.ID)
+// Update saves the item and bumps its version.
+func (r *ItemRepository) Update(ctx context.Context, item *model.Item) error {
+	_, err := r.update(ctx, item, "")
 	return err
 }
+
+// UpdateIfVersion saves the item only if its stored version is still version,
+// reporting false when someone else updated it first.
+func (r *ItemRepository) UpdateIfVersion(ctx context.Context, item *model.Item, version int) (bool, error) {
+	return r.update(ctx, item, " AND version = ?", version)
+}
+
+func (r *ItemRepository) update(ctx context.Context, item *model.Item, cond string, condArgs ...interface{}) (bool, error) {
+	attributes, err := encodeAttributes(item.Attributes)
+	if err != nil {
+		return false, err
+	}
+	query := `UPDATE items SET name=?, price=?, description=?, user_id=?, buyer_id=?, status=?, ai_negotiation_enabled=?, min_price=?, image_url=?, initial_price=?, auto_approve_answers=?, auto_approve_min_price=?, prompt_version=?, sold_at=?, category_id=?, brand=?, item_condition=?, attributes=?, shipping_method=?, shipping_payer=?, ship_from=?, days_to_ship=?, size_class=?, shipping_fee=?, version=version+1 WHERE id=?` + cond
+	args := append([]interface{}{item.Name, item.Price, item.Description, item.UserID, item.BuyerID, item.Status, item.AINegotiationEnabled, item.MinPrice, item.ImageURL, item.InitialPrice, item.AutoApproveAnswers, item.AutoApproveMinPrice, item.PromptVersion, item.SoldAt, item.CategoryID, nullString(item.Brand), nullString(item.Condition), attributes,
+		nullString(item.ShippingMethod), nullString(item.ShippingPayer), nullString(item.ShipFrom), nullString(item.DaysToShip), nullString(item.SizeClass), item.ShippingFee, item.ID}, condArgs...)
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if n == 0 {
+		return false, nil
+	}
+	item.Version++
+	return true, nil
+}
+
+// itemDetailsColumns scans the nullable classification columns.
+type itemDetailsColumns struct {
+	categoryID sql.NullString
+	brand      sql.NullString
+	condition  sql.NullString
+	attributes []byte
+}
+
+func (c *itemDetailsColumns) decode() (model.ItemDetails, error) {
+	d := model.ItemDetails{Brand: c.brand.String, Condition: c.condition.String}
+	if c.categoryID.Valid {
+		d.CategoryID = &c.categoryID.String
+	}
+	if len(c.attributes) > 0 {
+		if err := json.Unmarshal(c.attributes, &d.Attributes); err != nil {
+			return d, err
+		}
+	}
+	return d, nil
+}
+
+// itemShippingColumns scans the nullable shipping columns.
+type itemShippingColumns struct {
+	method, payer, from, daysToShip, sizeClass sql.NullString
+	fee                                        sql.NullInt64
+}
+
+func (c *itemShippingColumns) decode(item *model.Item) {
+	item.ShippingOptions = model.ShippingOptions{
+		ShippingMethod: c.method.String,
+		ShippingPayer:  c.payer.String,
+		ShipFrom:       c.from.String,
+		DaysToShip:     c.daysToShip.String,
+		SizeClass:      c.sizeClass.String,
+	}
+	if c.fee.Valid {
+		fee := int(c.fee.Int64)
+		item.ShippingFee = &fee
+	}
+}
+
+// encodeAttributes stores no attributes as NULL.
+func encodeAttributes(attributes map[string]string) (interface{}, error) {
+	if len(attributes) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal(attributes)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
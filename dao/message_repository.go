@@ -1,33 +1,53 @@
 package dao
 
 import (
+	"context"
 	"database/sql"
 	"hackathon-backend/model"
+	"time"
 )
 
 type MessageRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
 func NewMessageRepository(db *sql.DB) *MessageRepository {
 	return &MessageRepository{db: db}
 }
 
-func (r *MessageRepository) CreateMessage(msg *model.Message) error {
-	query := `INSERT INTO messages (id, item_id, sender_id, content, is_ai_response, is_approved, suggested_price, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
-	_, err := r.db.Exec(query, msg.ID, msg.ItemID, msg.SenderID, msg.Content, msg.IsAIResponse, msg.IsApproved, msg.SuggestedPrice, msg.CreatedAt)
+func (r *MessageRepository) CreateMessage(ctx context.Context, msg *model.Message) error {
+	query := `INSERT INTO messages (id, item_id, buyer_id, channel, reply_to_id, sender_id, content, is_ai_response, is_approved, suggested_price, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, msg.ID, msg.ItemID, nullIfEmpty(msg.BuyerID), msg.Channel, nullIfEmpty(msg.ReplyToID), msg.SenderID, msg.Content, msg.IsAIResponse, msg.IsApproved, msg.SuggestedPrice, msg.CreatedAt)
 	return err
 }
 
-func (r *MessageRepository) GetMessagesByItemID(itemID string) ([]model.Message, error) {
-	query := `SELECT m.id, m.item_id, m.sender_id, u.name as sender_name, m.content, m.is_ai_response, m.is_approved, m.suggested_price, m.created_at, l.ai_reasoning 
+// nullIfEmpty stores optional string keys as NULL rather than ''.
+func nullIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// GetMessagesByThread returns the private negotiation thread between the seller and one buyer.
+func (r *MessageRepository) GetMessagesByThread(ctx context.Context, itemID string, buyerID string) ([]model.Message, error) {
+	return r.queryMessages(ctx, `m.item_id = ? AND m.channel = ? AND m.buyer_id = ?`, itemID, model.ChannelNegotiation, buyerID)
+}
+
+// GetMessagesByChannel returns every message of an item in a channel (e.g. public Q&A).
+func (r *MessageRepository) GetMessagesByChannel(ctx context.Context, itemID string, channel string) ([]model.Message, error) {
+	return r.queryMessages(ctx, `m.item_id = ? AND m.channel = ?`, itemID, channel)
+}
+
+func (r *MessageRepository) queryMessages(ctx context.Context, where string, args ...interface{}) ([]model.Message, error) {
+	query := `SELECT m.id, m.item_id, m.buyer_id, m.channel, m.reply_to_id, m.sender_id, u.name as sender_name, m.content, m.is_ai_response, m.is_approved, m.suggested_price, m.created_at, l.ai_reasoning, l.ai_decision, l.proposed_price, l.counter_price
               FROM messages m 
-              LEFT JOIN negotiation_logs l ON m.item_id = l.item_id AND ABS(TIMESTAMPDIFF(SECOND, m.created_at, l.log_time)) < 2 AND m.is_ai_response = TRUE
+              LEFT JOIN negotiation_logs l ON l.message_id = m.id
               LEFT JOIN users u ON m.sender_id = u.id
-              WHERE m.item_id = ? 
-              ORDER BY m.created_at ASC`
+              WHERE ` + where + `
+              ORDER BY m.created_at ASC, m.id ASC` // ULIDs break ties within the same second
               
-	rows, err := r.db.Query(query, itemID)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -39,29 +59,106 @@ func (r *MessageRepository) GetMessagesByItemID(itemID string) ([]model.Message,
         var reasoning sql.NullString
         var suggestedPrice sql.NullInt64
         var senderName sql.NullString
-		if err := rows.Scan(&msg.ID, &msg.ItemID, &msg.SenderID, &senderName, &msg.Content, &msg.IsAIResponse, &msg.IsApproved, &suggestedPrice, &msg.CreatedAt, &reasoning); err != nil {
+        var decision sql.NullString
+        var detectedPrice, counterPrice sql.NullInt64
+        var buyerID, replyToID sql.NullString
+		if err := rows.Scan(&msg.ID, &msg.ItemID, &buyerID, &msg.Channel, &replyToID, &msg.SenderID, &senderName, &msg.Content, &msg.IsAIResponse, &msg.IsApproved, &suggestedPrice, &msg.CreatedAt, &reasoning, &decision, &detectedPrice, &counterPrice); err != nil {
 			return nil, err
 		}
         if senderName.Valid {
             msg.SenderName = senderName.String
         }
+        msg.BuyerID = buyerID.String
+        msg.ReplyToID = replyToID.String
         if reasoning.Valid {
             msg.AIReasoning = reasoning.String
         }
         if suggestedPrice.Valid {
             val := int(suggestedPrice.Int64)
             msg.SuggestedPrice = &val
+        }
+        if decision.Valid {
+            msg.AIDecision = decision.String
+        }
+        if detectedPrice.Valid && detectedPrice.Int64 > 0 {
+            val := int(detectedPrice.Int64)
+            msg.DetectedPrice = &val
+        }
+        if counterPrice.Valid && counterPrice.Int64 > 0 {
+            val := int(counterPrice.Int64)
+            msg.CounterPrice = &val
         }
 		msgs = append(msgs, msg)
 	}
-	return msgs, nil
+	return msgs, rows.Err()
+}
+
+// GetThreads lists the negotiation threads viewerID takes part in, as seller or
+// as buyer, most recently active first. itemID narrows it to one item ("" for all).
+// Counts are from the viewer's perspective: buyers never see unapproved drafts,
+// and PendingDrafts is only counted on the viewer's own listings.
+func (r *MessageRepository) GetThreads(ctx context.Context, viewerID string, itemID string) ([]model.Thread, error) {
+	query := `SELECT m.item_id, i.name, m.buyer_id, u.name,
+                     SUM(CASE WHEN m.is_approved OR i.user_id = ? THEN 1 ELSE 0 END),
+                     MAX(m.created_at),
+                     (SELECT x.content FROM messages x
+                      WHERE x.item_id = m.item_id AND x.channel = m.channel AND x.buyer_id = m.buyer_id
+                        AND (x.is_approved OR i.user_id = ?)
+                      ORDER BY x.created_at DESC, x.id DESC LIMIT 1),
+                     SUM(CASE WHEN m.sender_id != ? AND (m.is_approved OR i.user_id = ?)
+                               AND m.id > COALESCE(r.last_read_message_id, '') THEN 1 ELSE 0 END),
+                     SUM(CASE WHEN i.user_id = ? AND m.is_ai_response AND NOT m.is_approved THEN 1 ELSE 0 END)
+              FROM messages m
+              JOIN items i ON i.id = m.item_id
+              LEFT JOIN users u ON m.buyer_id = u.id
+              LEFT JOIN message_reads r ON r.item_id = m.item_id AND r.buyer_id = m.buyer_id AND r.user_id = ?
+              WHERE m.channel = ? AND m.buyer_id IS NOT NULL AND (i.user_id = ? OR m.buyer_id = ?)`
+	args := []interface{}{viewerID, viewerID, viewerID, viewerID, viewerID, viewerID, model.ChannelNegotiation, viewerID, viewerID}
+	if itemID != "" {
+		query += ` AND m.item_id = ?`
+		args = append(args, itemID)
+	}
+	query += `
+              GROUP BY m.item_id, i.name, i.user_id, m.channel, m.buyer_id, u.name, r.last_read_message_id
+              ORDER BY MAX(m.created_at) DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var threads []model.Thread
+	for rows.Next() {
+		var t model.Thread
+		var buyerName, lastContent sql.NullString
+		if err := rows.Scan(&t.ItemID, &t.ItemName, &t.BuyerID, &buyerName, &t.MessageCount, &t.LastMessageAt, &lastContent, &t.UnreadCount, &t.PendingDrafts); err != nil {
+			return nil, err
+		}
+		t.BuyerName = buyerName.String
+		t.LastMessage = lastContent.String
+		threads = append(threads, t)
+	}
+	return threads, rows.Err()
+}
+
+// MarkThreadRead moves userID's read marker in a thread forward to lastMessageID.
+// ULIDs sort by time, so the marker never moves backwards.
+func (r *MessageRepository) MarkThreadRead(ctx context.Context, itemID string, buyerID string, userID string, lastMessageID string) error {
+	query := `INSERT INTO message_reads (item_id, buyer_id, user_id, last_read_message_id, read_at) VALUES (?, ?, ?, ?, ?)
+              ON DUPLICATE KEY UPDATE
+                  last_read_message_id = GREATEST(last_read_message_id, VALUES(last_read_message_id)),
+                  read_at = VALUES(read_at)`
+	_, err := r.db.ExecContext(ctx, query, itemID, buyerID, userID, lastMessageID, time.Now())
+	return err
 }
 
-func (r *MessageRepository) GetMessageByID(id string) (*model.Message, error) {
-    query := `SELECT id, item_id, sender_id, content, is_ai_response, is_approved, suggested_price, created_at FROM messages WHERE id = ?`
+func (r *MessageRepository) GetMessageByID(ctx context.Context, id string) (*model.Message, error) {
+    query := `SELECT id, item_id, buyer_id, channel, reply_to_id, sender_id, content, is_ai_response, is_approved, suggested_price, created_at FROM messages WHERE id = ?`
     var m model.Message
     var suggestedPrice sql.NullInt64
-    err := r.db.QueryRow(query, id).Scan(&m.ID, &m.ItemID, &m.SenderID, &m.Content, &m.IsAIResponse, &m.IsApproved, &suggestedPrice, &m.CreatedAt)
+    var buyerID, replyToID sql.NullString
+    err := r.db.QueryRowContext(ctx, query, id).Scan(&m.ID, &m.ItemID, &buyerID, &m.Channel, &replyToID, &m.SenderID, &m.Content, &m.IsAIResponse, &m.IsApproved, &suggestedPrice, &m.CreatedAt)
     if err != nil {
         return nil, err
     }
@@ -69,22 +166,37 @@ func (r *MessageRepository) GetMessageByID(id string) (*model.Message, error) {
         val := int(suggestedPrice.Int64)
         m.SuggestedPrice = &val
     }
+    m.BuyerID = buyerID.String
+    m.ReplyToID = replyToID.String
     return &m, nil
 }
 
-func (r *MessageRepository) ApproveMessage(messageID string) error {
+func (r *MessageRepository) ApproveMessage(ctx context.Context, messageID string) error {
     query := `UPDATE messages SET is_approved = TRUE WHERE id = ?`
-    _, err := r.db.Exec(query, messageID)
+    _, err := r.db.ExecContext(ctx, query, messageID)
+    return err
+}
+
+func (r *MessageRepository) UnapproveMessage(ctx context.Context, messageID string) error {
+    query := `UPDATE messages SET is_approved = FALSE WHERE id = ?`
+    _, err := r.db.ExecContext(ctx, query, messageID)
     return err
 }
 
-func (r *MessageRepository) DeleteMessage(id string) error {
-	_, err := r.db.Exec("DELETE FROM messages WHERE id = ?", id)
+func (r *MessageRepository) DeleteMessage(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM messages WHERE id = ?", id)
+	return err
+}
+
+// SetNegotiationOutcome records what the seller did with the AI draft a log produced.
+// Call it before deleting a draft: the link is cleared when the message goes away.
+func (r *MessageRepository) SetNegotiationOutcome(ctx context.Context, messageID string, outcome string) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE negotiation_logs SET outcome = ? WHERE message_id = ?", outcome, messageID)
 	return err
 }
 
-func (r *MessageRepository) CreateNegotiationLog(log *model.NegotiationLog) error {
-	query := `INSERT INTO negotiation_logs (id, item_id, user_id, proposed_price, ai_decision, counter_price, ai_reasoning, log_time) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
-	_, err := r.db.Exec(query, log.ID, log.ItemID, log.UserID, log.ProposedPrice, log.AIDecision, log.CounterPrice, log.AIReasoning, log.LogTime)
+func (r *MessageRepository) CreateNegotiationLog(ctx context.Context, log *model.NegotiationLog) error {
+	query := `INSERT INTO negotiation_logs (id, item_id, user_id, message_id, proposed_price, ai_decision, counter_price, ai_reasoning, auto_approved, prompt_version, outcome, log_time) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, log.ID, log.ItemID, log.UserID, log.MessageID, log.ProposedPrice, log.AIDecision, log.CounterPrice, log.AIReasoning, log.AutoApproved, log.PromptVersion, log.Outcome, log.LogTime)
 	return err
 }
@@ -0,0 +1,154 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"hackathon-backend/model"
+)
+
+// NegotiationRepository holds the read-side (reporting) queries over negotiation_logs.
+// Logs are written through MessageRepository alongside the AI message they explain.
+type NegotiationRepository struct {
+	db *sql.DB
+}
+
+func NewNegotiationRepository(db *sql.DB) *NegotiationRepository {
+	return &NegotiationRepository{db: db}
+}
+
+func (r *NegotiationRepository) GetPromptVersionReport(ctx context.Context) ([]model.PromptVersionReport, error) {
+	// 1. Decision mix per prompt version ("ACCEPT (RETRY)" counts as ACCEPT)
+	query := `
+		SELECT prompt_version,
+		       COUNT(*),
+		       SUM(CASE WHEN ai_decision NOT LIKE 'ANSWER%' THEN 1 ELSE 0 END),
+		       SUM(CASE WHEN ai_decision LIKE 'ACCEPT%' THEN 1 ELSE 0 END)
+		FROM negotiation_logs
+		WHERE prompt_version IS NOT NULL AND prompt_version != ''
+		GROUP BY prompt_version
+		ORDER BY prompt_version
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reports []model.PromptVersionReport
+	index := make(map[string]int)
+	for rows.Next() {
+		var rep model.PromptVersionReport
+		if err := rows.Scan(&rep.PromptVersion, &rep.Decisions, &rep.PriceDecisions, &rep.Accepts); err != nil {
+			return nil, err
+		}
+		if rep.PriceDecisions > 0 {
+			rep.AcceptanceRate = float64(rep.Accepts) / float64(rep.PriceDecisions)
+		}
+		index[rep.PromptVersion] = len(reports)
+		reports = append(reports, rep)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// 2. Final price of conversations (item, buyer) that ended in a sale to that buyer
+	query = `
+		SELECT c.prompt_version, COUNT(*), AVG(i.price), AVG(i.price / NULLIF(i.initial_price, 0))
+		FROM (
+			SELECT DISTINCT item_id, user_id, prompt_version
+			FROM negotiation_logs
+			WHERE prompt_version IS NOT NULL AND prompt_version != ''
+		) c
+		JOIN items i ON i.id = c.item_id AND i.buyer_id = c.user_id AND i.status = 'sold'
+		GROUP BY c.prompt_version
+	`
+	saleRows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer saleRows.Close()
+
+	for saleRows.Next() {
+		var version string
+		var sold int
+		var avgPrice, avgRatio sql.NullFloat64
+		if err := saleRows.Scan(&version, &sold, &avgPrice, &avgRatio); err != nil {
+			return nil, err
+		}
+		i, ok := index[version]
+		if !ok {
+			continue
+		}
+		reports[i].SoldCount = sold
+		if avgPrice.Valid {
+			reports[i].AvgFinalPrice = avgPrice.Float64
+		}
+		if avgRatio.Valid {
+			reports[i].AvgFinalPriceRatio = avgRatio.Float64
+		}
+	}
+	if err := saleRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+// GetSellerItems returns the seller's listings (excluding deleted ones) with the fields stats need.
+func (r *NegotiationRepository) GetSellerItems(ctx context.Context, sellerID string) ([]model.Item, error) {
+	query := `
+		SELECT id, name, price, initial_price, status, created_at, sold_at
+		FROM items
+		WHERE user_id = ? AND status != 'deleted'
+		ORDER BY created_at DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []model.Item
+	for rows.Next() {
+		var item model.Item
+		var soldAt sql.NullTime
+		if err := rows.Scan(&item.ID, &item.Name, &item.Price, &item.InitialPrice, &item.Status, &item.CreatedAt, &soldAt); err != nil {
+			return nil, err
+		}
+		if soldAt.Valid {
+			item.SoldAt = &soldAt.Time
+		}
+		item.UserID = sellerID
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// GetSellerLogs returns every negotiation log on the seller's items.
+func (r *NegotiationRepository) GetSellerLogs(ctx context.Context, sellerID string) ([]model.NegotiationLogStat, error) {
+	query := `
+		SELECT l.item_id, l.proposed_price, l.ai_decision, l.outcome, l.auto_approved
+		FROM negotiation_logs l
+		JOIN items i ON i.id = l.item_id
+		WHERE i.user_id = ? AND i.status != 'deleted'
+	`
+	rows, err := r.db.QueryContext(ctx, query, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logs []model.NegotiationLogStat
+	for rows.Next() {
+		var l model.NegotiationLogStat
+		var outcome sql.NullString
+		var autoApproved sql.NullBool
+		if err := rows.Scan(&l.ItemID, &l.ProposedPrice, &l.AIDecision, &outcome, &autoApproved); err != nil {
+			return nil, err
+		}
+		l.Outcome = outcome.String
+		l.AutoApproved = autoApproved.Bool
+		logs = append(logs, l)
+	}
+	return logs, rows.Err()
+}
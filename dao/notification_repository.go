@@ -0,0 +1,148 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"hackathon-backend/model"
+)
+
+type NotificationRepository struct {
+	db *sql.DB
+}
+
+func NewNotificationRepository(db *sql.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// Create stores the notification, reporting false if one with the same ID
+// already exists (a redelivered event).
+func (r *NotificationRepository) Create(ctx context.Context, n *model.Notification) (bool, error) {
+	query := `INSERT INTO notifications (id, user_id, type, title, body, item_id, message_id, is_read, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE id = id`
+	res, err := r.db.ExecContext(ctx, query, n.ID, n.UserID, n.Type, n.Title, n.Body, nullIfEmpty(n.ItemID), nullIfEmpty(n.MessageID), n.IsRead, n.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+	rows, err := res.RowsAffected()
+	return rows == 1, err
+}
+
+// GetByUserID lists a user's notifications, newest first.
+func (r *NotificationRepository) GetByUserID(ctx context.Context, userID string, unreadOnly bool, limit int) ([]model.Notification, error) {
+	query := `SELECT id, user_id, type, title, body, item_id, message_id, is_read, created_at FROM notifications WHERE user_id = ?`
+	if unreadOnly {
+		query += ` AND is_read = FALSE`
+	}
+	query += ` ORDER BY created_at DESC, id DESC LIMIT ?`
+	rows, err := r.db.QueryContext(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []model.Notification
+	for rows.Next() {
+		var n model.Notification
+		var itemID, messageID sql.NullString
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Type, &n.Title, &n.Body, &itemID, &messageID, &n.IsRead, &n.CreatedAt); err != nil {
+			return nil, err
+		}
+		n.ItemID = itemID.String
+		n.MessageID = messageID.String
+		notifications = append(notifications, n)
+	}
+	return notifications, rows.Err()
+}
+
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID string) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM notifications WHERE user_id = ? AND is_read = FALSE", userID).Scan(&count)
+	return count, err
+}
+
+// MarkRead marks one notification read. It reports false if the notification
+// does not exist or belongs to someone else.
+func (r *NotificationRepository) MarkRead(ctx context.Context, id string, userID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM notifications WHERE id = ? AND user_id = ?)", id, userID).Scan(&exists)
+	if err != nil || !exists {
+		return false, err
+	}
+	_, err = r.db.ExecContext(ctx, "UPDATE notifications SET is_read = TRUE WHERE id = ? AND user_id = ?", id, userID)
+	return true, err
+}
+
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID string) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE notifications SET is_read = TRUE WHERE user_id = ? AND is_read = FALSE", userID)
+	return err
+}
+
+// ------ Preferences ------
+
+func (r *NotificationRepository) GetPreferences(ctx context.Context, userID string) ([]model.NotificationPreference, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT user_id, event_type, channel, enabled FROM notification_preferences WHERE user_id = ?", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prefs []model.NotificationPreference
+	for rows.Next() {
+		var p model.NotificationPreference
+		if err := rows.Scan(&p.UserID, &p.EventType, &p.Channel, &p.Enabled); err != nil {
+			return nil, err
+		}
+		prefs = append(prefs, p)
+	}
+	return prefs, rows.Err()
+}
+
+func (r *NotificationRepository) UpsertPreference(ctx context.Context, p *model.NotificationPreference) error {
+	query := `INSERT INTO notification_preferences (user_id, event_type, channel, enabled) VALUES (?, ?, ?, ?)
+        ON DUPLICATE KEY UPDATE enabled = VALUES(enabled)`
+	_, err := r.db.ExecContext(ctx, query, p.UserID, p.EventType, p.Channel, p.Enabled)
+	return err
+}
+
+// ------ Web push subscriptions ------
+
+// SavePushSubscription registers a browser endpoint; re-subscribing the same endpoint refreshes its keys.
+func (r *NotificationRepository) SavePushSubscription(ctx context.Context, s *model.PushSubscription) error {
+	query := `INSERT INTO push_subscriptions (id, user_id, endpoint, p256dh, auth, created_at) VALUES (?, ?, ?, ?, ?, ?)
+        ON DUPLICATE KEY UPDATE user_id = VALUES(user_id), p256dh = VALUES(p256dh), auth = VALUES(auth)`
+	_, err := r.db.ExecContext(ctx, query, s.ID, s.UserID, s.Endpoint, s.P256dh, s.Auth, s.CreatedAt)
+	return err
+}
+
+func (r *NotificationRepository) GetPushSubscriptions(ctx context.Context, userID string) ([]model.PushSubscription, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT id, user_id, endpoint, p256dh, auth, created_at FROM push_subscriptions WHERE user_id = ?", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subs []model.PushSubscription
+	for rows.Next() {
+		var s model.PushSubscription
+		if err := rows.Scan(&s.ID, &s.UserID, &s.Endpoint, &s.P256dh, &s.Auth, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		subs = append(subs, s)
+	}
+	return subs, rows.Err()
+}
+
+func (r *NotificationRepository) DeletePushSubscription(ctx context.Context, userID string, endpoint string) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM push_subscriptions WHERE user_id = ? AND endpoint = ?", userID, endpoint)
+	return err
+}
+
+// DeletePushSubscriptionsByEndpoint drops endpoints the push service reported as gone.
+func (r *NotificationRepository) DeletePushSubscriptionsByEndpoint(ctx context.Context, endpoints []string) error {
+	for _, endpoint := range endpoints {
+		if _, err := r.db.ExecContext(ctx, "DELETE FROM push_subscriptions WHERE endpoint = ?", endpoint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"hackathon-backend/model"
+)
+
+type OrderCaseRepository struct {
+	db DBTX
+}
+
+func NewOrderCaseRepository(db *sql.DB) *OrderCaseRepository {
+	return &OrderCaseRepository{db: db}
+}
+
+const orderCaseColumns = `id, item_id, kind, status, seller_id, buyer_id, opened_by, reason, details, outcome, resolution_note, closed_by, created_at, closed_at`
+
+func (r *OrderCaseRepository) Insert(ctx context.Context, c *model.OrderCase) error {
+	query := `INSERT INTO order_cases (id, item_id, kind, status, seller_id, buyer_id, opened_by, reason, details, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, c.ID, c.ItemID, c.Kind, c.Status, c.SellerID, c.BuyerID, c.OpenedBy, c.Reason, c.Details, c.CreatedAt)
+	return err
+}
+
+// Close saves the final status, outcome and who closed the case.
+func (r *OrderCaseRepository) Close(ctx context.Context, c *model.OrderCase) error {
+	query := `UPDATE order_cases SET status = ?, outcome = ?, resolution_note = ?, closed_by = ?, closed_at = ? WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, c.Status, nullString(c.Outcome), nullString(c.ResolutionNote), nullString(c.ClosedBy), c.ClosedAt, c.ID)
+	return err
+}
+
+// GetByID returns nil if there is no such case.
+func (r *OrderCaseRepository) GetByID(ctx context.Context, id string) (*model.OrderCase, error) {
+	c, err := scanOrderCase(r.db.QueryRowContext(ctx, `SELECT `+orderCaseColumns+` FROM order_cases WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return c, err
+}
+
+// GetOpenByItemID returns the item's open case, or nil.
+func (r *OrderCaseRepository) GetOpenByItemID(ctx context.Context, itemID string) (*model.OrderCase, error) {
+	c, err := scanOrderCase(r.db.QueryRowContext(ctx, `SELECT `+orderCaseColumns+` FROM order_cases WHERE item_id = ? AND status = 'open' LIMIT 1`, itemID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return c, err
+}
+
+// GetByItemID lists the item's cases, newest first.
+func (r *OrderCaseRepository) GetByItemID(ctx context.Context, itemID string) ([]model.OrderCase, error) {
+	return r.query(ctx, `SELECT `+orderCaseColumns+` FROM order_cases WHERE item_id = ? ORDER BY created_at DESC`, itemID)
+}
+
+// GetByStatus lists cases of a kind in a status, oldest first, for the admin queue.
+func (r *OrderCaseRepository) GetByStatus(ctx context.Context, kind string, status string, limit int) ([]model.OrderCase, error) {
+	return r.query(ctx, `SELECT `+orderCaseColumns+` FROM order_cases WHERE kind = ? AND status = ? ORDER BY created_at ASC LIMIT ?`, kind, status, limit)
+}
+
+func (r *OrderCaseRepository) query(ctx context.Context, query string, args ...interface{}) ([]model.OrderCase, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cases []model.OrderCase
+	for rows.Next() {
+		c, err := scanOrderCase(rows)
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, *c)
+	}
+	return cases, rows.Err()
+}
+
+func scanOrderCase(row rowScanner) (*model.OrderCase, error) {
+	var c model.OrderCase
+	var outcome, note, closedBy sql.NullString
+	var closedAt sql.NullTime
+	if err := row.Scan(&c.ID, &c.ItemID, &c.Kind, &c.Status, &c.SellerID, &c.BuyerID, &c.OpenedBy, &c.Reason, &c.Details,
+		&outcome, &note, &closedBy, &c.CreatedAt, &closedAt); err != nil {
+		return nil, err
+	}
+	c.Outcome = outcome.String
+	c.ResolutionNote = note.String
+	c.ClosedBy = closedBy.String
+	if closedAt.Valid {
+		c.ClosedAt = &closedAt.Time
+	}
+	return &c, nil
+}
+
+func (r *OrderCaseRepository) InsertMessage(ctx context.Context, m *model.CaseMessage) error {
+	query := `INSERT INTO order_case_messages (id, case_id, sender_id, content, evidence_url, created_at) VALUES (?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, m.ID, m.CaseID, m.SenderID, m.Content, nullString(m.EvidenceURL), m.CreatedAt)
+	return err
+}
+
+// GetMessages lists the case's messages, oldest first.
+func (r *OrderCaseRepository) GetMessages(ctx context.Context, caseID string) ([]model.CaseMessage, error) {
+	query := `SELECT id, case_id, sender_id, content, COALESCE(evidence_url, ''), created_at FROM order_case_messages WHERE case_id = ? ORDER BY created_at ASC`
+	rows, err := r.db.QueryContext(ctx, query, caseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []model.CaseMessage
+	for rows.Next() {
+		var m model.CaseMessage
+		if err := rows.Scan(&m.ID, &m.CaseID, &m.SenderID, &m.Content, &m.EvidenceURL, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
@@ -0,0 +1,74 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"hackathon-backend/model"
+)
+
+type PriceHistoryRepository struct {
+	db DBTX
+}
+
+func NewPriceHistoryRepository(db *sql.DB) *PriceHistoryRepository {
+	return &PriceHistoryRepository{db: db}
+}
+
+func (r *PriceHistoryRepository) Insert(ctx context.Context, c *model.PriceChange) error {
+	query := `INSERT INTO item_price_history (id, item_id, old_price, new_price, source, changed_by, message_id, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	var changedBy sql.NullString
+	if c.ChangedBy != "" {
+		changedBy = sql.NullString{String: c.ChangedBy, Valid: true}
+	}
+	_, err := r.db.ExecContext(ctx, query, c.ID, c.ItemID, c.OldPrice, c.NewPrice, c.Source, changedBy, c.MessageID, c.CreatedAt)
+	return err
+}
+
+// GetByItemID returns the item's price changes, oldest first.
+func (r *PriceHistoryRepository) GetByItemID(ctx context.Context, itemID string) ([]model.PriceChange, error) {
+	query := `SELECT id, item_id, old_price, new_price, source, changed_by, message_id, created_at FROM item_price_history WHERE item_id = ? ORDER BY created_at ASC, id ASC`
+	rows, err := r.db.QueryContext(ctx, query, itemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []model.PriceChange
+	for rows.Next() {
+		c, err := scanPriceChange(rows)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, *c)
+	}
+	return changes, rows.Err()
+}
+
+// GetNegotiatedByMessageID returns the latest price change the approved message
+// made, or nil.
+func (r *PriceHistoryRepository) GetNegotiatedByMessageID(ctx context.Context, messageID string) (*model.PriceChange, error) {
+	query := `SELECT id, item_id, old_price, new_price, source, changed_by, message_id, created_at FROM item_price_history WHERE message_id = ? AND source = ? ORDER BY created_at DESC, id DESC LIMIT 1`
+	c, err := scanPriceChange(r.db.QueryRowContext(ctx, query, messageID, model.PriceSourceNegotiation))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return c, err
+}
+
+func scanPriceChange(row rowScanner) (*model.PriceChange, error) {
+	var c model.PriceChange
+	var oldPrice sql.NullInt64
+	var changedBy, messageID sql.NullString
+	if err := row.Scan(&c.ID, &c.ItemID, &oldPrice, &c.NewPrice, &c.Source, &changedBy, &messageID, &c.CreatedAt); err != nil {
+		return nil, err
+	}
+	if oldPrice.Valid {
+		val := int(oldPrice.Int64)
+		c.OldPrice = &val
+	}
+	c.ChangedBy = changedBy.String
+	if messageID.Valid {
+		c.MessageID = &messageID.String
+	}
+	return &c, nil
+}
@@ -0,0 +1,69 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// DBTX is the part of *sql.DB and *sql.Tx the repositories use, so the same
+// repository code runs inside or outside a transaction.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Tx groups the repositories that take part in a usecase transaction.
+type Tx struct {
+	Items     *ItemRepository
+	Messages  *MessageRepository
+	FAQs      *FAQRepository
+	Events    *EventRepository
+	Jobs      *AIJobRepository
+	Prices    *PriceHistoryRepository
+	Addresses *AddressRepository
+	Cases     *OrderCaseRepository
+}
+
+type TxManager struct {
+	db *sql.DB
+}
+
+func NewTxManager(db *sql.DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+// WithTx runs fn in a transaction, committing if it returns nil and rolling back otherwise.
+// Cancelling ctx rolls the transaction back.
+func (m *TxManager) WithTx(ctx context.Context, fn func(tx *Tx) error) (err error) {
+	sqlTx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			sqlTx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			if rbErr := sqlTx.Rollback(); rbErr != nil {
+				err = fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+			}
+		}
+	}()
+
+	if err = fn(&Tx{
+		Items:     &ItemRepository{db: sqlTx},
+		Messages:  &MessageRepository{db: sqlTx},
+		FAQs:      &FAQRepository{db: sqlTx},
+		Events:    &EventRepository{db: sqlTx},
+		Jobs:      &AIJobRepository{db: sqlTx},
+		Prices:    &PriceHistoryRepository{db: sqlTx},
+		Addresses: &AddressRepository{db: sqlTx},
+		Cases:     &OrderCaseRepository{db: sqlTx},
+	}); err != nil {
+		return err
+	}
+	return sqlTx.Commit()
+}
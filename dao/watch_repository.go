@@ -0,0 +1,150 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"hackathon-backend/model"
+)
+
+type WatchRepository struct {
+	db DBTX
+}
+
+func NewWatchRepository(db *sql.DB) *WatchRepository {
+	return &WatchRepository{db: db}
+}
+
+// Watch starts or updates an explicit watch. A new target price clears the
+// alert state so the user hears about the next qualifying price.
+func (r *WatchRepository) Watch(ctx context.Context, itemID string, userID string, targetPrice *int) error {
+	query := `INSERT INTO item_watches (item_id, user_id, watching, target_price) VALUES (?, ?, TRUE, ?)
+		ON DUPLICATE KEY UPDATE watching = TRUE, target_price = VALUES(target_price), notified_price = NULL`
+	_, err := r.db.ExecContext(ctx, query, itemID, userID, targetPrice)
+	return err
+}
+
+// Unwatch ends the explicit watch; a like keeps the row (and alerts) alive.
+func (r *WatchRepository) Unwatch(ctx context.Context, itemID string, userID string) error {
+	if _, err := r.db.ExecContext(ctx, `UPDATE item_watches SET watching = FALSE, target_price = NULL WHERE item_id = ? AND user_id = ?`, itemID, userID); err != nil {
+		return err
+	}
+	return r.deleteUnused(ctx, itemID, userID)
+}
+
+func (r *WatchRepository) SetLiked(ctx context.Context, itemID string, userID string, liked bool) error {
+	if liked {
+		query := `INSERT INTO item_watches (item_id, user_id, liked) VALUES (?, ?, TRUE) ON DUPLICATE KEY UPDATE liked = TRUE`
+		_, err := r.db.ExecContext(ctx, query, itemID, userID)
+		return err
+	}
+	if _, err := r.db.ExecContext(ctx, `UPDATE item_watches SET liked = FALSE WHERE item_id = ? AND user_id = ?`, itemID, userID); err != nil {
+		return err
+	}
+	return r.deleteUnused(ctx, itemID, userID)
+}
+
+func (r *WatchRepository) deleteUnused(ctx context.Context, itemID string, userID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM item_watches WHERE item_id = ? AND user_id = ? AND NOT watching AND NOT liked`, itemID, userID)
+	return err
+}
+
+// Get returns nil if the user neither watches nor likes the item.
+func (r *WatchRepository) Get(ctx context.Context, itemID string, userID string) (*model.ItemWatch, error) {
+	query := `SELECT item_id, user_id, watching, liked, target_price, notified_price, created_at FROM item_watches WHERE item_id = ? AND user_id = ?`
+	w, err := scanWatch(r.db.QueryRowContext(ctx, query, itemID, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return w, err
+}
+
+func (r *WatchRepository) GetByItemID(ctx context.Context, itemID string) ([]model.ItemWatch, error) {
+	query := `SELECT item_id, user_id, watching, liked, target_price, notified_price, created_at FROM item_watches WHERE item_id = ?`
+	rows, err := r.db.QueryContext(ctx, query, itemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var watches []model.ItemWatch
+	for rows.Next() {
+		w, err := scanWatch(rows)
+		if err != nil {
+			return nil, err
+		}
+		watches = append(watches, *w)
+	}
+	return watches, rows.Err()
+}
+
+// GetByUserID lists the user's watches with their items, newest first. Deleted items are left out.
+func (r *WatchRepository) GetByUserID(ctx context.Context, userID string) ([]model.ItemWatch, error) {
+	query := `
+		SELECT w.item_id, w.user_id, w.watching, w.liked, w.target_price, w.notified_price, w.created_at,
+		       i.name, i.price, i.initial_price, i.status, i.user_id, COALESCE(i.image_url, '')
+		FROM item_watches w
+		JOIN items i ON i.id = w.item_id
+		WHERE w.user_id = ? AND i.status != 'deleted'
+		ORDER BY w.created_at DESC`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var watches []model.ItemWatch
+	for rows.Next() {
+		var w model.ItemWatch
+		var target, notified sql.NullInt64
+		item := &model.Item{}
+		if err := rows.Scan(&w.ItemID, &w.UserID, &w.Watching, &w.Liked, &target, &notified, &w.CreatedAt,
+			&item.Name, &item.Price, &item.InitialPrice, &item.Status, &item.UserID, &item.ImageURL); err != nil {
+			return nil, err
+		}
+		item.ID = w.ItemID
+		w.TargetPrice = nullIntPtr(target)
+		w.NotifiedPrice = nullIntPtr(notified)
+		w.Item = item
+		watches = append(watches, w)
+	}
+	return watches, rows.Err()
+}
+
+// ClaimAlert records that the user is alerted about price, reporting false if
+// they already were for that price or a lower one. Claiming before sending
+// keeps a redelivered event from alerting anyone twice.
+func (r *WatchRepository) ClaimAlert(ctx context.Context, itemID string, userID string, price int) (bool, error) {
+	query := `UPDATE item_watches SET notified_price = ? WHERE item_id = ? AND user_id = ? AND (notified_price IS NULL OR notified_price > ?)`
+	res, err := r.db.ExecContext(ctx, query, price, itemID, userID, price)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// ClearNotifiedBelow forgets alerts for prices under price, after the price went
+// back up, so dropping to them again is news again.
+func (r *WatchRepository) ClearNotifiedBelow(ctx context.Context, itemID string, price int) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE item_watches SET notified_price = NULL WHERE item_id = ? AND notified_price < ?`, itemID, price)
+	return err
+}
+
+func scanWatch(row rowScanner) (*model.ItemWatch, error) {
+	var w model.ItemWatch
+	var target, notified sql.NullInt64
+	if err := row.Scan(&w.ItemID, &w.UserID, &w.Watching, &w.Liked, &target, &notified, &w.CreatedAt); err != nil {
+		return nil, err
+	}
+	w.TargetPrice = nullIntPtr(target)
+	w.NotifiedPrice = nullIntPtr(notified)
+	return &w, nil
+}
+
+func nullIntPtr(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	i := int(v.Int64)
+	return &i
+}
@@ -0,0 +1,208 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"hackathon-backend/model"
+	"strings"
+	"time"
+)
+
+type WebhookRepository struct {
+	db *sql.DB
+}
+
+func NewWebhookRepository(db *sql.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// ------ Endpoints ------
+
+func (r *WebhookRepository) CreateEndpoint(ctx context.Context, e *model.WebhookEndpoint) error {
+	query := `INSERT INTO webhook_endpoints (id, user_id, url, secret, event_types, is_active, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, e.ID, e.UserID, e.URL, e.Secret, strings.Join(e.EventTypes, ","), e.IsActive, e.CreatedAt)
+	return err
+}
+
+func (r *WebhookRepository) GetEndpointByID(ctx context.Context, id string) (*model.WebhookEndpoint, error) {
+	query := `SELECT id, user_id, url, secret, event_types, is_active, created_at FROM webhook_endpoints WHERE id = ?`
+	e, err := scanEndpoint(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return e, err
+}
+
+func (r *WebhookRepository) GetEndpointsByUserID(ctx context.Context, userID string) ([]model.WebhookEndpoint, error) {
+	query := `SELECT id, user_id, url, secret, event_types, is_active, created_at FROM webhook_endpoints WHERE user_id = ? ORDER BY created_at ASC`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var endpoints []model.WebhookEndpoint
+	for rows.Next() {
+		e, err := scanEndpoint(rows)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, *e)
+	}
+	return endpoints, rows.Err()
+}
+
+func (r *WebhookRepository) DeleteEndpoint(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM webhook_endpoints WHERE id = ?", id)
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanEndpoint(row rowScanner) (*model.WebhookEndpoint, error) {
+	var e model.WebhookEndpoint
+	var eventTypes string
+	if err := row.Scan(&e.ID, &e.UserID, &e.URL, &e.Secret, &eventTypes, &e.IsActive, &e.CreatedAt); err != nil {
+		return nil, err
+	}
+	e.EventTypes = []string{}
+	if eventTypes != "" {
+		e.EventTypes = strings.Split(eventTypes, ",")
+	}
+	return &e, nil
+}
+
+// ------ Outbox ------
+
+// CreateDelivery queues a delivery; an event already queued for the endpoint is skipped.
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, d *model.WebhookDelivery) error {
+	query := `INSERT IGNORE INTO webhook_deliveries (id, endpoint_id, event_id, event_type, payload, status, attempts, next_attempt_at, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, d.ID, d.EndpointID, d.EventID, d.EventType, d.Payload, d.Status, d.Attempts, d.NextAttemptAt, d.CreatedAt)
+	return err
+}
+
+// ClaimDueDeliveries picks pending deliveries whose retry time has come and leases
+// them until leaseUntil, so another dispatcher (or this one after a crash) only
+// picks them up again once the lease runs out.
+func (r *WebhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]model.WebhookDelivery, error) {
+	query := `
+        SELECT d.id, d.endpoint_id, d.event_id, d.event_type, d.payload, d.status, d.attempts, d.next_attempt_at, d.created_at, e.url, e.secret
+        FROM webhook_deliveries d
+        JOIN webhook_endpoints e ON e.id = d.endpoint_id
+        WHERE d.status = ? AND d.next_attempt_at <= ? AND e.is_active = TRUE
+        ORDER BY d.next_attempt_at ASC
+        LIMIT ?`
+	rows, err := r.db.QueryContext(ctx, query, model.DeliveryPending, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	var due []model.WebhookDelivery
+	for rows.Next() {
+		var d model.WebhookDelivery
+		if err := rows.Scan(&d.ID, &d.EndpointID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts, &d.NextAttemptAt, &d.CreatedAt, &d.URL, &d.Secret); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		due = append(due, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var claimed []model.WebhookDelivery
+	for _, d := range due {
+		res, err := r.db.ExecContext(ctx, `UPDATE webhook_deliveries SET next_attempt_at = ? WHERE id = ? AND status = ? AND next_attempt_at = ?`,
+			leaseUntil, d.ID, model.DeliveryPending, d.NextAttemptAt)
+		if err != nil {
+			return nil, err
+		}
+		if n, _ := res.RowsAffected(); n == 1 {
+			claimed = append(claimed, d)
+		}
+	}
+	return claimed, nil
+}
+
+// RecordAttempt logs one attempt and moves the delivery to its next state.
+func (r *WebhookRepository) RecordAttempt(ctx context.Context, a *model.WebhookAttempt, d *model.WebhookDelivery) error {
+	query := `INSERT INTO webhook_delivery_attempts (id, delivery_id, status_code, error, duration_ms, attempted_at) VALUES (?, ?, ?, ?, ?, ?)`
+	if _, err := r.db.ExecContext(ctx, query, a.ID, a.DeliveryID, a.StatusCode, nullIfEmpty(a.Error), a.DurationMs, a.AttemptedAt); err != nil {
+		return err
+	}
+	query = `UPDATE webhook_deliveries SET status = ?, attempts = ?, next_attempt_at = ?, last_error = ?, delivered_at = ? WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, d.Status, d.Attempts, d.NextAttemptAt, nullIfEmpty(d.LastError), d.DeliveredAt, d.ID)
+	return err
+}
+
+// GetDeliveriesByEndpoint returns the newest deliveries of an endpoint with their attempt log.
+func (r *WebhookRepository) GetDeliveriesByEndpoint(ctx context.Context, endpointID string, limit int) ([]model.WebhookDelivery, error) {
+	query := `
+        SELECT id, endpoint_id, event_id, event_type, payload, status, attempts, next_attempt_at, last_error, created_at, delivered_at
+        FROM webhook_deliveries
+        WHERE endpoint_id = ?
+        ORDER BY created_at DESC, id DESC
+        LIMIT ?`
+	rows, err := r.db.QueryContext(ctx, query, endpointID, limit)
+	if err != nil {
+		return nil, err
+	}
+	var deliveries []model.WebhookDelivery
+	index := make(map[string]int)
+	for rows.Next() {
+		var d model.WebhookDelivery
+		var lastError sql.NullString
+		var deliveredAt sql.NullTime
+		if err := rows.Scan(&d.ID, &d.EndpointID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts, &d.NextAttemptAt, &lastError, &d.CreatedAt, &deliveredAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		d.LastError = lastError.String
+		if deliveredAt.Valid {
+			d.DeliveredAt = &deliveredAt.Time
+		}
+		index[d.ID] = len(deliveries)
+		deliveries = append(deliveries, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil || len(deliveries) == 0 {
+		return deliveries, err
+	}
+
+	query = `
+        SELECT a.id, a.delivery_id, a.status_code, a.error, a.duration_ms, a.attempted_at
+        FROM webhook_delivery_attempts a
+        JOIN webhook_deliveries d ON d.id = a.delivery_id
+        WHERE d.endpoint_id = ? AND d.created_at >= ?
+        ORDER BY a.attempted_at ASC, a.id ASC`
+	rows, err = r.db.QueryContext(ctx, query, endpointID, deliveries[len(deliveries)-1].CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var a model.WebhookAttempt
+		var attemptErr sql.NullString
+		if err := rows.Scan(&a.ID, &a.DeliveryID, &a.StatusCode, &attemptErr, &a.DurationMs, &a.AttemptedAt); err != nil {
+			return nil, err
+		}
+		a.Error = attemptErr.String
+		if i, ok := index[a.DeliveryID]; ok {
+			deliveries[i].AttemptLog = append(deliveries[i].AttemptLog, a)
+		}
+	}
+	return deliveries, rows.Err()
+}
+
+// RetryDelivery puts a delivery back in the queue for an immediate attempt.
+func (r *WebhookRepository) RetryDelivery(ctx context.Context, id string, endpointID string, now time.Time) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `UPDATE webhook_deliveries SET status = ?, next_attempt_at = ? WHERE id = ? AND endpoint_id = ? AND status <> ?`,
+		model.DeliveryPending, now, id, endpointID, model.DeliveryDelivered)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
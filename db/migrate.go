@@ -3,35 +3,22 @@
 package main
 
 import (
-	"database/sql"
 	"fmt"
+	"hackathon-backend/config"
+	"hackathon-backend/dao"
 	"log"
-	"os"
-
-	_ "github.com/go-sql-driver/mysql"
 )
 
 func main() {
-	user := "user"
-	pwd := "password"
-	host := "tcp(127.0.0.1:3306)"
-	dbName := "hackathon_db"
-
-	if os.Getenv("MYSQL_USER") != "" { user = os.Getenv("MYSQL_USER") }
-	if os.Getenv("MYSQL_PWD") != "" { pwd = os.Getenv("MYSQL_PWD") }
-	if os.Getenv("MYSQL_HOST") != "" { host = os.Getenv("MYSQL_HOST") }
-	if os.Getenv("MYSQL_DATABASE") != "" { dbName = os.Getenv("MYSQL_DATABASE") }
-
-	dsn := fmt.Sprintf("%s:%s@%s/%s?parseTime=true&loc=Local", user, pwd, host, dbName)
-	db, err := sql.Open("mysql", dsn)
+	cfg, err := config.Load()
 	if err != nil {
-		log.Fatal(err)
+		log.Fatal("Invalid configuration:\n", err)
 	}
-	defer db.Close()
-
-	if err := db.Ping(); err != nil {
+	db, err := dao.OpenDB(cfg.DB)
+	if err != nil {
 		log.Fatal("Failed to connect to DB:", err)
 	}
+	defer db.Close()
 
 	queries := []string{
 		"ALTER TABLE items ADD COLUMN views_count INT DEFAULT 0",
@@ -3,76 +3,118 @@
 package main
 
 import (
-	"database/sql"
+	"errors"
 	"fmt"
+	"hackathon-backend/config"
+	"hackathon-backend/dao"
 	"io/ioutil"
 	"log"
 	"os"
 	"strings"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 )
 
 func main() {
 	// DB Connection
-	user := os.Getenv("MYSQL_USER")
-	if user == "" {
-		user = "user"
-	}
-	pwd := os.Getenv("MYSQL_PWD")
-	if pwd == "" {
-		pwd = "password"
-	}
-	host := os.Getenv("MYSQL_HOST")
-	if host == "" {
-		host = "tcp(127.0.0.1:3306)"
-	}
-	dbName := os.Getenv("MYSQL_DATABASE")
-	if dbName == "" {
-		dbName = "hackathon_db"
-	}
-
-	dsn := fmt.Sprintf("%s:%s@%s/%s?parseTime=true&loc=Local", user, pwd, host, dbName)
-	db, err := sql.Open("mysql", dsn)
+	cfg, err := config.Load()
 	if err != nil {
-		log.Fatal(err)
+		log.Fatal("Invalid configuration:\n", err)
 	}
-	defer db.Close()
-
-	if err := db.Ping(); err != nil {
+	db, err := dao.OpenDB(cfg.DB)
+	if err != nil {
 		log.Fatal("Failed to connect to DB:", err)
 	}
+	defer db.Close()
 	fmt.Println("Connected to Database for Migration!")
 
-	// Read Migration File
-	content, err := ioutil.ReadFile("db/migration_phase3_5.sql")
+	// Read Migration File (defaults to phase 3.5; pass another .sql path as the first argument)
+	path := "db/migration_phase3_5.sql"
+	if len(os.Args) > 1 {
+		path = os.Args[1]
+	}
+	content, err := ioutil.ReadFile(path)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	queries := strings.Split(string(content), ";")
-	for _, query := range queries {
-		query = strings.TrimSpace(query)
-		if query == "" {
-			continue
-		}
-		// Skip comments
-        if strings.HasPrefix(query, "--") {
-            continue
-        }
-
+	for _, query := range splitStatements(string(content)) {
 		log.Printf("Executing: %s", query)
 		_, err := db.Exec(query)
 		if err != nil {
 			// MySQL 8.0 doesn't support IF NOT EXISTS for columns easily in one line without procedure,
-			// So we ignore "Duplicate column name" error (Code 1060)
-			if strings.Contains(err.Error(), "Duplicate column name") {
+			// so a rerun skips "Duplicate column name" (Code 1060). Anything else stops the migration.
+			var mysqlErr *mysql.MySQLError
+			if errors.As(err, &mysqlErr) && mysqlErr.Number == 1060 {
 				log.Printf("Skipping duplicate column error: %v", err)
-			} else {
-				log.Printf("Error executing query: %v", err)
+				continue
 			}
+			log.Fatalf("Migration %s failed: %v", path, err)
 		}
 	}
 
-	fmt.Println("Migration Phase 3.5 Completed Successfully!")
+	fmt.Printf("Migration %s Completed Successfully!\n", path)
+}
+
+// splitStatements splits a SQL file on the semicolons that end statements,
+// leaving those inside quoted strings and comments alone, and drops the comments.
+func splitStatements(sql string) []string {
+	var statements []string
+	var current strings.Builder
+	flush := func() {
+		if stmt := strings.TrimSpace(current.String()); stmt != "" {
+			statements = append(statements, stmt)
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			// Copy the quoted string; a backslash or a doubled quote escapes the quote
+			j := i + 1
+			for j < len(sql) {
+				if sql[j] == '\\' && c != '`' {
+					j += 2
+					continue
+				}
+				if sql[j] == c {
+					if j+1 < len(sql) && sql[j+1] == c {
+						j += 2
+						continue
+					}
+					break
+				}
+				j++
+			}
+			if j >= len(sql) {
+				j = len(sql) - 1
+			}
+			current.WriteString(sql[i : j+1])
+			i = j
+		case c == '#' || (c == '-' && strings.HasPrefix(sql[i:], "--") && (i+2 == len(sql) || sql[i+2] == ' ' || sql[i+2] == '\t' || sql[i+2] == '\n' || sql[i+2] == '\r')):
+			// Line comment: skip to the end of the line
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				i = len(sql)
+			} else {
+				i += end - 1
+			}
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				i = len(sql)
+			} else {
+				i += end + 3
+			}
+			current.WriteByte(' ')
+		case c == ';':
+			flush()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	flush()
+	return statements
 }
@@ -0,0 +1,26 @@
+-- Address book. Personal fields are encrypted by the app (AES-GCM, ADDRESS_ENCRYPTION_KEY) into
+-- sealed_fields. Only the prefecture is stored in the clear, for shipping quotes.
+CREATE TABLE IF NOT EXISTS user_addresses (
+    id VARCHAR(128) PRIMARY KEY COMMENT 'ULID',
+    user_id VARCHAR(128) NOT NULL,
+    prefecture CHAR(2) NOT NULL COMMENT 'JIS prefecture code',
+    sealed_fields TEXT NOT NULL COMMENT 'Encrypted label, name, postal code, city, lines, phone',
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_user_addresses_user (user_id, created_at)
+);
+
+-- The address chosen at purchase, copied so later edits to the address book don't change it.
+-- Shown only to the item's seller and buyer.
+CREATE TABLE IF NOT EXISTS purchase_addresses (
+    item_id VARCHAR(128) PRIMARY KEY,
+    buyer_id VARCHAR(128) NOT NULL,
+    address_id VARCHAR(128) DEFAULT NULL COMMENT 'Address book entry it was copied from',
+    prefecture CHAR(2) NOT NULL,
+    sealed_fields TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+    FOREIGN KEY (buyer_id) REFERENCES users(id)
+);
@@ -0,0 +1,20 @@
+-- AI reply jobs: one per user message that needs an AI draft, worked by the background pool
+CREATE TABLE IF NOT EXISTS ai_jobs (
+    id VARCHAR(128) PRIMARY KEY COMMENT 'ULID',
+    kind VARCHAR(30) NOT NULL COMMENT 'negotiation_reply, public_answer',
+    item_id VARCHAR(128) NOT NULL,
+    buyer_id VARCHAR(128) NULL COMMENT 'Negotiation thread, NULL for public questions',
+    message_id VARCHAR(128) NOT NULL COMMENT 'The message being answered',
+    status VARCHAR(20) NOT NULL DEFAULT 'queued' COMMENT 'queued, running, succeeded, failed, skipped',
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL DEFAULT 5,
+    next_attempt_at TIMESTAMP(6) NOT NULL COMMENT 'Retry time, also the worker lease while running',
+    last_error TEXT,
+    result_message_id VARCHAR(128) NULL COMMENT 'AI message produced',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+    FOREIGN KEY (message_id) REFERENCES messages(id) ON DELETE CASCADE,
+    INDEX idx_ai_jobs_due (status, next_attempt_at),
+    INDEX idx_ai_jobs_message (message_id)
+);
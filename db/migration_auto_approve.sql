@@ -0,0 +1,6 @@
+-- Seller opt-in for publishing AI drafts without manual approval
+ALTER TABLE items ADD COLUMN auto_approve_answers BOOLEAN DEFAULT FALSE;
+ALTER TABLE items ADD COLUMN auto_approve_min_price INT DEFAULT NULL COMMENT 'Auto-approve ACCEPT/COUNTER drafts at or above this price';
+
+-- Record which drafts were published without review
+ALTER TABLE negotiation_logs ADD COLUMN auto_approved BOOLEAN DEFAULT FALSE;
@@ -0,0 +1,34 @@
+-- Category tree. attribute_schema lists the extra attributes items in the
+-- category (and its subcategories) can carry: [{"key", "label", "type": "text|number|select", "options", "required"}]
+CREATE TABLE IF NOT EXISTS categories (
+    id VARCHAR(64) PRIMARY KEY COMMENT 'Slug',
+    parent_id VARCHAR(64) DEFAULT NULL,
+    name VARCHAR(100) NOT NULL,
+    attribute_schema JSON DEFAULT NULL,
+    sort_order INT NOT NULL DEFAULT 0,
+    FOREIGN KEY (parent_id) REFERENCES categories(id),
+    INDEX idx_categories_parent (parent_id, sort_order)
+);
+
+INSERT IGNORE INTO categories (id, parent_id, name, attribute_schema, sort_order) VALUES
+    ('fashion', NULL, 'ファッション', '[{"key": "size", "label": "サイズ", "type": "select", "options": ["XS", "S", "M", "L", "XL", "XXL", "FREE"]}, {"key": "color", "label": "色", "type": "text"}]', 10),
+    ('fashion-mens', 'fashion', 'メンズ', NULL, 11),
+    ('fashion-womens', 'fashion', 'レディース', NULL, 12),
+    ('electronics', NULL, '家電・スマホ・カメラ', NULL, 20),
+    ('electronics-smartphones', 'electronics', 'スマートフォン', '[{"key": "storage_gb", "label": "ストレージ(GB)", "type": "number"}, {"key": "color", "label": "色", "type": "text"}, {"key": "sim_free", "label": "SIMフリー", "type": "select", "options": ["yes", "no"]}]', 21),
+    ('electronics-pcs', 'electronics', 'パソコン', '[{"key": "cpu", "label": "CPU", "type": "text"}, {"key": "memory_gb", "label": "メモリ(GB)", "type": "number"}, {"key": "storage_gb", "label": "ストレージ(GB)", "type": "number"}]', 22),
+    ('electronics-cameras', 'electronics', 'カメラ', NULL, 23),
+    ('books-media', NULL, '本・音楽・ゲーム', NULL, 30),
+    ('books', 'books-media', '本', '[{"key": "author", "label": "著者", "type": "text"}, {"key": "isbn", "label": "ISBN", "type": "text"}]', 31),
+    ('games', 'books-media', 'ゲーム', '[{"key": "platform", "label": "機種", "type": "select", "options": ["Switch", "PS5", "PS4", "Xbox", "PC", "other"], "required": true}]', 32),
+    ('hobbies', NULL, 'ホビー・おもちゃ', NULL, 40),
+    ('other', NULL, 'その他', NULL, 90);
+
+-- Item classification. item_condition: CONDITION is a reserved word in MySQL.
+ALTER TABLE items ADD COLUMN category_id VARCHAR(64) DEFAULT NULL;
+ALTER TABLE items ADD COLUMN brand VARCHAR(100) DEFAULT NULL;
+ALTER TABLE items ADD COLUMN item_condition VARCHAR(20) DEFAULT NULL COMMENT 'new, like_new, good, fair, poor';
+ALTER TABLE items ADD COLUMN attributes JSON DEFAULT NULL COMMENT 'Values for the category attribute_schema';
+ALTER TABLE items ADD FOREIGN KEY (category_id) REFERENCES categories(id);
+ALTER TABLE items ADD INDEX idx_items_category (category_id, status);
+ALTER TABLE items ADD INDEX idx_items_brand (brand);
@@ -0,0 +1,32 @@
+-- Domain event outbox, per-subscriber delivery records and daily analytics counters
+CREATE TABLE IF NOT EXISTS domain_events (
+    id VARCHAR(128) PRIMARY KEY COMMENT 'ULID',
+    type VARCHAR(50) NOT NULL COMMENT 'item.created, item.sold, message.created, draft.pending, draft.approved',
+    aggregate_id VARCHAR(128) NOT NULL COMMENT 'Item ID',
+    payload JSON NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP(6) NOT NULL COMMENT 'Retry time, also the dispatcher lease',
+    last_error TEXT,
+    created_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    dispatched_at TIMESTAMP NULL DEFAULT NULL,
+    INDEX idx_domain_events_due (dispatched_at, next_attempt_at),
+    INDEX idx_domain_events_aggregate (aggregate_id, created_at)
+);
+
+CREATE TABLE IF NOT EXISTS domain_event_consumers (
+    event_id VARCHAR(128) NOT NULL,
+    subscriber VARCHAR(50) NOT NULL COMMENT 'notifications, webhooks, sse, analytics',
+    handled_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (event_id, subscriber),
+    FOREIGN KEY (event_id) REFERENCES domain_events(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS analytics_daily_events (
+    day DATE NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    count INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, event_type)
+);
+
+-- Webhook deliveries are now enqueued by a retried subscriber: one delivery per event per endpoint
+ALTER TABLE webhook_deliveries ADD UNIQUE KEY uq_webhook_deliveries_event (endpoint_id, event_id);
@@ -0,0 +1,2 @@
+-- Optimistic concurrency: bumped on every item update and served as the ETag
+ALTER TABLE items ADD COLUMN version INT NOT NULL DEFAULT 1;
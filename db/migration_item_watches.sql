@@ -0,0 +1,15 @@
+-- Item watches: users who watch or like an item and hear about price drops
+CREATE TABLE IF NOT EXISTS item_watches (
+    item_id VARCHAR(128) NOT NULL,
+    user_id VARCHAR(128) NOT NULL,
+    watching BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Explicit watch',
+    liked BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Likes watch implicitly',
+    target_price INT DEFAULT NULL COMMENT 'Alert only at or below this price, NULL alerts on every drop',
+    notified_price INT DEFAULT NULL COMMENT 'Last price the user was alerted about',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (item_id, user_id),
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_item_watches_user (user_id, created_at)
+);
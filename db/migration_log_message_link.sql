@@ -0,0 +1,21 @@
+-- Link each negotiation log to the AI message it produced
+ALTER TABLE negotiation_logs ADD COLUMN message_id VARCHAR(128) DEFAULT NULL COMMENT 'AI message this decision produced';
+
+-- Backfill: pair each log with the nearest AI message of the same item written within 2 seconds
+-- (the old timestamp join), keeping only pairs that are each other's closest match
+UPDATE negotiation_logs l
+JOIN (
+    SELECT l2.id AS log_id,
+           m.id AS message_id,
+           ROW_NUMBER() OVER (PARTITION BY l2.id ORDER BY ABS(TIMESTAMPDIFF(SECOND, m.created_at, l2.log_time)), m.id) AS rn_log,
+           ROW_NUMBER() OVER (PARTITION BY m.id ORDER BY ABS(TIMESTAMPDIFF(SECOND, m.created_at, l2.log_time)), l2.id) AS rn_msg
+    FROM negotiation_logs l2
+    JOIN messages m ON m.item_id = l2.item_id
+        AND m.is_ai_response = TRUE
+        AND ABS(TIMESTAMPDIFF(SECOND, m.created_at, l2.log_time)) < 2
+    WHERE l2.message_id IS NULL
+) best ON best.log_id = l.id AND best.rn_log = 1 AND best.rn_msg = 1
+SET l.message_id = best.message_id;
+
+-- Drafts that get rejected or regenerated are deleted, but their logs are kept for analytics
+ALTER TABLE negotiation_logs ADD CONSTRAINT fk_negotiation_logs_message FOREIGN KEY (message_id) REFERENCES messages(id) ON DELETE SET NULL;
@@ -0,0 +1,11 @@
+-- Per-participant read markers for negotiation threads
+CREATE TABLE IF NOT EXISTS message_reads (
+    item_id VARCHAR(128) NOT NULL,
+    buyer_id VARCHAR(128) NOT NULL COMMENT 'Thread key',
+    user_id VARCHAR(128) NOT NULL COMMENT 'Participant (seller or the buyer)',
+    last_read_message_id VARCHAR(128) NOT NULL COMMENT 'ULID of the last message seen',
+    read_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (item_id, buyer_id, user_id),
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
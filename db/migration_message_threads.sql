@@ -0,0 +1,33 @@
+-- Scope messages to a (item, buyer) thread, plus a separate public channel
+ALTER TABLE messages ADD COLUMN buyer_id VARCHAR(128) DEFAULT NULL COMMENT 'Thread key: the buyer this conversation is with (NULL for public)';
+ALTER TABLE messages ADD COLUMN channel VARCHAR(20) NOT NULL DEFAULT 'negotiation' COMMENT 'negotiation, public';
+
+-- Buyer messages belong to the sender's own thread
+UPDATE messages m
+JOIN items i ON i.id = m.item_id
+SET m.buyer_id = m.sender_id
+WHERE m.sender_id != i.user_id AND m.buyer_id IS NULL;
+
+-- Seller (and AI) messages belong to the thread of the buyer who wrote last before them.
+-- Resolved into a helper table first: MySQL can't read messages while updating it.
+CREATE TABLE message_thread_backfill AS
+SELECT s.id AS message_id,
+       (SELECT b.sender_id FROM messages b
+        WHERE b.item_id = s.item_id AND b.sender_id != i.user_id
+          AND (b.created_at < s.created_at OR (b.created_at = s.created_at AND b.id < s.id))
+        ORDER BY b.created_at DESC, b.id DESC LIMIT 1) AS buyer_id
+FROM messages s
+JOIN items i ON i.id = s.item_id
+WHERE s.sender_id = i.user_id AND s.buyer_id IS NULL;
+
+UPDATE messages m
+JOIN message_thread_backfill t ON t.message_id = m.id
+SET m.buyer_id = t.buyer_id
+WHERE t.buyer_id IS NOT NULL;
+
+DROP TABLE message_thread_backfill;
+
+-- Seller posts that precede any buyer have no thread: treat them as public announcements
+UPDATE messages SET channel = 'public' WHERE buyer_id IS NULL;
+
+CREATE INDEX idx_messages_thread ON messages (item_id, channel, buyer_id, created_at);
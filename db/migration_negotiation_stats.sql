@@ -0,0 +1,11 @@
+-- When the item was purchased (time-to-sale metric)
+ALTER TABLE items ADD COLUMN sold_at TIMESTAMP NULL DEFAULT NULL;
+
+-- What the seller did with each AI draft: pending, approved, rejected, regenerated
+ALTER TABLE negotiation_logs ADD COLUMN outcome VARCHAR(20) DEFAULT NULL;
+
+-- Backfill from the linked message where it still exists. Deleted drafts stay unknown (NULL)
+UPDATE negotiation_logs l
+JOIN messages m ON m.id = l.message_id
+SET l.outcome = IF(m.is_approved, 'approved', 'pending')
+WHERE l.outcome IS NULL;
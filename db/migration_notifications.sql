@@ -0,0 +1,36 @@
+-- Notification center: in-app notifications, per-user delivery preferences, web push subscriptions
+CREATE TABLE IF NOT EXISTS notifications (
+    id VARCHAR(128) PRIMARY KEY COMMENT 'ULID',
+    user_id VARCHAR(128) NOT NULL COMMENT 'Recipient',
+    type VARCHAR(50) NOT NULL COMMENT 'draft_pending, reply_approved, item_sold',
+    title VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    item_id VARCHAR(128) DEFAULT NULL,
+    message_id VARCHAR(128) DEFAULT NULL,
+    is_read BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+    FOREIGN KEY (message_id) REFERENCES messages(id) ON DELETE SET NULL,
+    INDEX idx_notifications_user (user_id, is_read, created_at)
+);
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id VARCHAR(128) NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    channel VARCHAR(20) NOT NULL COMMENT 'in_app, email, web_push',
+    enabled BOOLEAN NOT NULL,
+    PRIMARY KEY (user_id, event_type, channel),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS push_subscriptions (
+    id VARCHAR(128) PRIMARY KEY COMMENT 'ULID',
+    user_id VARCHAR(128) NOT NULL,
+    endpoint VARCHAR(512) NOT NULL,
+    p256dh VARCHAR(255) NOT NULL,
+    auth VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE KEY uq_push_subscriptions_endpoint (endpoint)
+);
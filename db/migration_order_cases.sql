@@ -0,0 +1,34 @@
+-- After-sale cases on a sold item: a cancellation request the other party accepts or
+-- declines, or a dispute an admin resolves. At most one case per item is open at a time.
+-- A refunded dispute moves the item to status 'cancelled', which listings leave out.
+CREATE TABLE IF NOT EXISTS order_cases (
+    id VARCHAR(128) PRIMARY KEY COMMENT 'ULID',
+    item_id VARCHAR(128) NOT NULL,
+    kind VARCHAR(20) NOT NULL COMMENT 'cancellation, dispute',
+    status VARCHAR(20) NOT NULL DEFAULT 'open' COMMENT 'open, accepted, declined, withdrawn, resolved',
+    seller_id VARCHAR(128) NOT NULL,
+    buyer_id VARCHAR(128) NOT NULL,
+    opened_by VARCHAR(128) NOT NULL,
+    reason VARCHAR(40) NOT NULL,
+    details TEXT NOT NULL,
+    outcome VARCHAR(20) DEFAULT NULL COMMENT 'refund, complete or relist, set when the sale is settled',
+    resolution_note TEXT DEFAULT NULL COMMENT 'Admin note on a resolved dispute',
+    closed_by VARCHAR(128) DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    closed_at TIMESTAMP NULL DEFAULT NULL,
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+    INDEX idx_order_cases_item (item_id, created_at),
+    INDEX idx_order_cases_status (status, kind, created_at)
+);
+
+-- Statements and evidence on a case, from either party or an admin.
+CREATE TABLE IF NOT EXISTS order_case_messages (
+    id VARCHAR(128) PRIMARY KEY COMMENT 'ULID',
+    case_id VARCHAR(128) NOT NULL,
+    sender_id VARCHAR(128) NOT NULL,
+    content TEXT NOT NULL,
+    evidence_url TEXT DEFAULT NULL COMMENT 'Photo or document backing the message',
+    created_at TIMESTAMP(3) DEFAULT CURRENT_TIMESTAMP(3),
+    FOREIGN KEY (case_id) REFERENCES order_cases(id) ON DELETE CASCADE,
+    INDEX idx_order_case_messages_case (case_id, created_at)
+);
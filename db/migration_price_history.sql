@@ -0,0 +1,17 @@
+-- Item price history: every price change with where it came from
+CREATE TABLE IF NOT EXISTS item_price_history (
+    id VARCHAR(128) PRIMARY KEY COMMENT 'ULID',
+    item_id VARCHAR(128) NOT NULL,
+    old_price INT DEFAULT NULL COMMENT 'NULL for the listing price',
+    new_price INT NOT NULL,
+    source VARCHAR(20) NOT NULL COMMENT 'listing, seller_edit, negotiation, revoke, promotion',
+    changed_by VARCHAR(128) DEFAULT NULL COMMENT 'User who made the change',
+    message_id VARCHAR(128) DEFAULT NULL COMMENT 'Approved negotiation message that set the price',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+    INDEX idx_item_price_history_item (item_id, created_at)
+);
+
+-- Backfill: existing items start their history at the current price
+INSERT INTO item_price_history (id, item_id, old_price, new_price, source, changed_by, created_at)
+SELECT CONCAT('backfill-', id), id, NULL, price, 'listing', user_id, created_at FROM items;
@@ -0,0 +1,6 @@
+-- Optional per-item pin of the Smart-Nego prompt template
+ALTER TABLE items ADD COLUMN prompt_version VARCHAR(32) DEFAULT NULL;
+
+-- Prompt template version that produced each AI decision
+ALTER TABLE negotiation_logs ADD COLUMN prompt_version VARCHAR(32) DEFAULT NULL;
+CREATE INDEX idx_negotiation_logs_prompt_version ON negotiation_logs (prompt_version);
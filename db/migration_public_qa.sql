@@ -0,0 +1,18 @@
+-- Public answers point at the question they answer
+ALTER TABLE messages ADD COLUMN reply_to_id VARCHAR(128) DEFAULT NULL COMMENT 'Question this public answer replies to';
+
+-- Approved public Q&A pairs, shown on the listing and fed to Smart-Nego
+CREATE TABLE IF NOT EXISTS item_faqs (
+    id VARCHAR(128) PRIMARY KEY COMMENT 'ULID',
+    item_id VARCHAR(128) NOT NULL,
+    question_message_id VARCHAR(128) NOT NULL,
+    answer_message_id VARCHAR(128) NOT NULL,
+    question TEXT NOT NULL,
+    answer TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+    FOREIGN KEY (question_message_id) REFERENCES messages(id) ON DELETE CASCADE,
+    FOREIGN KEY (answer_message_id) REFERENCES messages(id) ON DELETE CASCADE,
+    UNIQUE KEY uq_item_faqs_answer (answer_message_id),
+    INDEX idx_item_faqs_item (item_id, created_at)
+);
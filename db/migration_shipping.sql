@@ -0,0 +1,7 @@
+-- Shipping options on listings and the shipping charged at purchase
+ALTER TABLE items ADD COLUMN shipping_method VARCHAR(20) DEFAULT NULL COMMENT 'rakuraku, yuyu, standard';
+ALTER TABLE items ADD COLUMN shipping_payer VARCHAR(10) DEFAULT NULL COMMENT 'seller, buyer';
+ALTER TABLE items ADD COLUMN ship_from CHAR(2) DEFAULT NULL COMMENT 'JIS prefecture code';
+ALTER TABLE items ADD COLUMN days_to_ship VARCHAR(10) DEFAULT NULL COMMENT '1-2, 2-3, 4-7';
+ALTER TABLE items ADD COLUMN size_class VARCHAR(10) DEFAULT NULL COMMENT 'small, compact, 60 ... 160';
+ALTER TABLE items ADD COLUMN shipping_fee INT DEFAULT NULL COMMENT 'Charged to the buyer at purchase';
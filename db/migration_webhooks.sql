@@ -0,0 +1,40 @@
+-- Outbound webhooks: per-user endpoints, delivery outbox and attempt log
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+    id VARCHAR(128) PRIMARY KEY COMMENT 'ULID',
+    user_id VARCHAR(128) NOT NULL,
+    url VARCHAR(2048) NOT NULL,
+    secret VARCHAR(128) NOT NULL COMMENT 'HMAC-SHA256 signing key',
+    event_types VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Comma separated, empty = all events',
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_webhook_endpoints_user (user_id)
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id VARCHAR(128) PRIMARY KEY COMMENT 'ULID',
+    endpoint_id VARCHAR(128) NOT NULL,
+    event_id VARCHAR(128) NOT NULL COMMENT 'Same for every endpoint receiving the event',
+    event_type VARCHAR(50) NOT NULL,
+    payload JSON NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' COMMENT 'pending, delivered, failed',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP(6) NOT NULL COMMENT 'Retry time, also the dispatcher lease',
+    last_error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP NULL DEFAULT NULL,
+    FOREIGN KEY (endpoint_id) REFERENCES webhook_endpoints(id) ON DELETE CASCADE,
+    INDEX idx_webhook_deliveries_due (status, next_attempt_at),
+    INDEX idx_webhook_deliveries_endpoint (endpoint_id, created_at)
+);
+
+CREATE TABLE IF NOT EXISTS webhook_delivery_attempts (
+    id VARCHAR(128) PRIMARY KEY COMMENT 'ULID',
+    delivery_id VARCHAR(128) NOT NULL,
+    status_code INT NOT NULL DEFAULT 0 COMMENT '0 = no response',
+    error TEXT,
+    duration_ms INT NOT NULL,
+    attempted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (delivery_id) REFERENCES webhook_deliveries(id) ON DELETE CASCADE,
+    INDEX idx_webhook_attempts_delivery (delivery_id)
+);
@@ -5,6 +5,32 @@ CREATE TABLE IF NOT EXISTS users (
     email VARCHAR(255) NOT NULL UNIQUE
 );
 
+-- Category tree. attribute_schema lists the extra attributes items in the
+-- category (and its subcategories) can carry: [{"key", "label", "type": "text|number|select", "options", "required"}]
+CREATE TABLE IF NOT EXISTS categories (
+    id VARCHAR(64) PRIMARY KEY COMMENT 'Slug',
+    parent_id VARCHAR(64) DEFAULT NULL,
+    name VARCHAR(100) NOT NULL,
+    attribute_schema JSON DEFAULT NULL,
+    sort_order INT NOT NULL DEFAULT 0,
+    FOREIGN KEY (parent_id) REFERENCES categories(id),
+    INDEX idx_categories_parent (parent_id, sort_order)
+);
+
+INSERT IGNORE INTO categories (id, parent_id, name, attribute_schema, sort_order) VALUES
+    ('fashion', NULL, 'ファッション', '[{"key": "size", "label": "サイズ", "type": "select", "options": ["XS", "S", "M", "L", "XL", "XXL", "FREE"]}, {"key": "color", "label": "色", "type": "text"}]', 10),
+    ('fashion-mens', 'fashion', 'メンズ', NULL, 11),
+    ('fashion-womens', 'fashion', 'レディース', NULL, 12),
+    ('electronics', NULL, '家電・スマホ・カメラ', NULL, 20),
+    ('electronics-smartphones', 'electronics', 'スマートフォン', '[{"key": "storage_gb", "label": "ストレージ(GB)", "type": "number"}, {"key": "color", "label": "色", "type": "text"}, {"key": "sim_free", "label": "SIMフリー", "type": "select", "options": ["yes", "no"]}]', 21),
+    ('electronics-pcs', 'electronics', 'パソコン', '[{"key": "cpu", "label": "CPU", "type": "text"}, {"key": "memory_gb", "label": "メモリ(GB)", "type": "number"}, {"key": "storage_gb", "label": "ストレージ(GB)", "type": "number"}]', 22),
+    ('electronics-cameras', 'electronics', 'カメラ', NULL, 23),
+    ('books-media', NULL, '本・音楽・ゲーム', NULL, 30),
+    ('books', 'books-media', '本', '[{"key": "author", "label": "著者", "type": "text"}, {"key": "isbn", "label": "ISBN", "type": "text"}]', 31),
+    ('games', 'books-media', 'ゲーム', '[{"key": "platform", "label": "機種", "type": "select", "options": ["Switch", "PS5", "PS4", "Xbox", "PC", "other"], "required": true}]', 32),
+    ('hobbies', NULL, 'ホビー・おもちゃ', NULL, 40),
+    ('other', NULL, 'その他', NULL, 90);
+
 -- Items table
 CREATE TABLE IF NOT EXISTS items (
     id VARCHAR(128) PRIMARY KEY COMMENT 'ULID',
@@ -13,13 +39,31 @@ CREATE TABLE IF NOT EXISTS items (
     description TEXT,
     user_id VARCHAR(128) NOT NULL COMMENT 'Seller ID',
     buyer_id VARCHAR(128) COMMENT 'Buyer ID',
-    status VARCHAR(20) DEFAULT 'on_sale' COMMENT 'on_sale, sold',
+    status VARCHAR(20) DEFAULT 'on_sale' COMMENT 'on_sale, sold, cancelled (refunded), deleted',
     FOREIGN KEY (buyer_id) REFERENCES users(id),
     views_count INT DEFAULT 0,
     ai_negotiation_enabled BOOLEAN DEFAULT FALSE,
     min_price INT,
+    auto_approve_answers BOOLEAN DEFAULT FALSE,
+    auto_approve_min_price INT DEFAULT NULL COMMENT 'Auto-approve ACCEPT/COUNTER drafts at or above this price',
+    prompt_version VARCHAR(32) DEFAULT NULL COMMENT 'Pinned Smart-Nego prompt version',
+    version INT NOT NULL DEFAULT 1 COMMENT 'Bumped on every update, served as the ETag',
+    category_id VARCHAR(64) DEFAULT NULL,
+    brand VARCHAR(100) DEFAULT NULL,
+    item_condition VARCHAR(20) DEFAULT NULL COMMENT 'new, like_new, good, fair, poor',
+    attributes JSON DEFAULT NULL COMMENT 'Values for the category attribute_schema',
+    shipping_method VARCHAR(20) DEFAULT NULL COMMENT 'rakuraku, yuyu, standard',
+    shipping_payer VARCHAR(10) DEFAULT NULL COMMENT 'seller, buyer',
+    ship_from CHAR(2) DEFAULT NULL COMMENT 'JIS prefecture code',
+    days_to_ship VARCHAR(10) DEFAULT NULL COMMENT '1-2, 2-3, 4-7',
+    size_class VARCHAR(10) DEFAULT NULL COMMENT 'small, compact, 60 ... 160',
+    shipping_fee INT DEFAULT NULL COMMENT 'Charged to the buyer at purchase',
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    sold_at TIMESTAMP NULL DEFAULT NULL,
     FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (category_id) REFERENCES categories(id),
+    INDEX idx_items_category (category_id, status),
+    INDEX idx_items_brand (brand),
     image_url LONGTEXT
 );
 
@@ -27,6 +71,9 @@ CREATE TABLE IF NOT EXISTS items (
 CREATE TABLE IF NOT EXISTS messages (
     id VARCHAR(128) PRIMARY KEY COMMENT 'ULID',
     item_id VARCHAR(128) NOT NULL,
+    buyer_id VARCHAR(128) DEFAULT NULL COMMENT 'Thread key: the buyer this conversation is with (NULL for public)',
+    channel VARCHAR(20) NOT NULL DEFAULT 'negotiation' COMMENT 'negotiation, public',
+    reply_to_id VARCHAR(128) DEFAULT NULL COMMENT 'Question this public answer replies to',
     sender_id VARCHAR(128) NOT NULL,
     content TEXT NOT NULL,
     is_ai_response BOOLEAN DEFAULT FALSE,
@@ -34,7 +81,8 @@ CREATE TABLE IF NOT EXISTS messages (
     suggested_price INT DEFAULT NULL COMMENT 'Price suggested by AI or Buyer',
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY (item_id) REFERENCES items(id),
-    FOREIGN KEY (sender_id) REFERENCES users(id)
+    FOREIGN KEY (sender_id) REFERENCES users(id),
+    INDEX idx_messages_thread (item_id, channel, buyer_id, created_at)
 );
 
 -- Negotiation Logs table
@@ -42,11 +90,274 @@ CREATE TABLE IF NOT EXISTS negotiation_logs (
     id VARCHAR(128) PRIMARY KEY COMMENT 'ULID',
     item_id VARCHAR(128) NOT NULL,
     user_id VARCHAR(128) NOT NULL COMMENT 'Buyer ID',
+    message_id VARCHAR(128) DEFAULT NULL COMMENT 'AI message this decision produced',
     proposed_price INT NOT NULL,
     ai_decision VARCHAR(50) NOT NULL COMMENT 'ACCEPT, REJECT, COUNTER, ANSWER',
     counter_price INT COMMENT 'Counter offer price if any',
     ai_reasoning TEXT,
+    auto_approved BOOLEAN DEFAULT FALSE COMMENT 'Published without seller review',
+    prompt_version VARCHAR(32) DEFAULT NULL COMMENT 'Prompt template version used',
+    outcome VARCHAR(20) DEFAULT NULL COMMENT 'pending, approved, rejected, regenerated',
     log_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (message_id) REFERENCES messages(id) ON DELETE SET NULL,
+    INDEX idx_negotiation_logs_prompt_version (prompt_version)
+);
+
+-- Item FAQ (approved public Q&A pairs)
+CREATE TABLE IF NOT EXISTS item_faqs (
+    id VARCHAR(128) PRIMARY KEY COMMENT 'ULID',
+    item_id VARCHAR(128) NOT NULL,
+    question_message_id VARCHAR(128) NOT NULL,
+    answer_message_id VARCHAR(128) NOT NULL,
+    question TEXT NOT NULL,
+    answer TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+    FOREIGN KEY (question_message_id) REFERENCES messages(id) ON DELETE CASCADE,
+    FOREIGN KEY (answer_message_id) REFERENCES messages(id) ON DELETE CASCADE,
+    UNIQUE KEY uq_item_faqs_answer (answer_message_id),
+    INDEX idx_item_faqs_item (item_id, created_at)
+);
+
+-- Read markers per thread participant
+CREATE TABLE IF NOT EXISTS message_reads (
+    item_id VARCHAR(128) NOT NULL,
+    buyer_id VARCHAR(128) NOT NULL COMMENT 'Thread key',
+    user_id VARCHAR(128) NOT NULL COMMENT 'Participant (seller or the buyer)',
+    last_read_message_id VARCHAR(128) NOT NULL COMMENT 'ULID of the last message seen',
+    read_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (item_id, buyer_id, user_id),
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Notifications (in-app notification center)
+CREATE TABLE IF NOT EXISTS notifications (
+    id VARCHAR(128) PRIMARY KEY COMMENT 'ULID',
+    user_id VARCHAR(128) NOT NULL COMMENT 'Recipient',
+    type VARCHAR(50) NOT NULL COMMENT 'draft_pending, reply_approved, item_sold',
+    title VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    item_id VARCHAR(128) DEFAULT NULL,
+    message_id VARCHAR(128) DEFAULT NULL,
+    is_read BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+    FOREIGN KEY (message_id) REFERENCES messages(id) ON DELETE SET NULL,
+    INDEX idx_notifications_user (user_id, is_read, created_at)
+);
+
+-- Per-user delivery channel preferences (missing rows use defaults)
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id VARCHAR(128) NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    channel VARCHAR(20) NOT NULL COMMENT 'in_app, email, web_push',
+    enabled BOOLEAN NOT NULL,
+    PRIMARY KEY (user_id, event_type, channel),
     FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
 );
+
+-- Browser web push subscriptions
+CREATE TABLE IF NOT EXISTS push_subscriptions (
+    id VARCHAR(128) PRIMARY KEY COMMENT 'ULID',
+    user_id VARCHAR(128) NOT NULL,
+    endpoint VARCHAR(512) NOT NULL,
+    p256dh VARCHAR(255) NOT NULL,
+    auth VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE KEY uq_push_subscriptions_endpoint (endpoint)
+);
+
+-- Webhook endpoints registered by users
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+    id VARCHAR(128) PRIMARY KEY COMMENT 'ULID',
+    user_id VARCHAR(128) NOT NULL,
+    url VARCHAR(2048) NOT NULL,
+    secret VARCHAR(128) NOT NULL COMMENT 'HMAC-SHA256 signing key',
+    event_types VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Comma separated, empty = all events',
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_webhook_endpoints_user (user_id)
+);
+
+-- Webhook outbox: one row per event per endpoint
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id VARCHAR(128) PRIMARY KEY COMMENT 'ULID',
+    endpoint_id VARCHAR(128) NOT NULL,
+    event_id VARCHAR(128) NOT NULL COMMENT 'Same for every endpoint receiving the event',
+    event_type VARCHAR(50) NOT NULL,
+    payload JSON NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' COMMENT 'pending, delivered, failed',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP(6) NOT NULL COMMENT 'Retry time, also the dispatcher lease',
+    last_error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP NULL DEFAULT NULL,
+    FOREIGN KEY (endpoint_id) REFERENCES webhook_endpoints(id) ON DELETE CASCADE,
+    UNIQUE KEY uq_webhook_deliveries_event (endpoint_id, event_id),
+    INDEX idx_webhook_deliveries_due (status, next_attempt_at),
+    INDEX idx_webhook_deliveries_endpoint (endpoint_id, created_at)
+);
+
+-- Webhook delivery log: one row per HTTP attempt
+CREATE TABLE IF NOT EXISTS webhook_delivery_attempts (
+    id VARCHAR(128) PRIMARY KEY COMMENT 'ULID',
+    delivery_id VARCHAR(128) NOT NULL,
+    status_code INT NOT NULL DEFAULT 0 COMMENT '0 = no response',
+    error TEXT,
+    duration_ms INT NOT NULL,
+    attempted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (delivery_id) REFERENCES webhook_deliveries(id) ON DELETE CASCADE,
+    INDEX idx_webhook_attempts_delivery (delivery_id)
+);
+
+-- Domain event outbox (written in the same transaction as the state change)
+CREATE TABLE IF NOT EXISTS domain_events (
+    id VARCHAR(128) PRIMARY KEY COMMENT 'ULID',
+    type VARCHAR(50) NOT NULL COMMENT 'item.created, item.sold, message.created, draft.pending, draft.approved',
+    aggregate_id VARCHAR(128) NOT NULL COMMENT 'Item ID',
+    payload JSON NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP(6) NOT NULL COMMENT 'Retry time, also the dispatcher lease',
+    last_error TEXT,
+    created_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    dispatched_at TIMESTAMP NULL DEFAULT NULL,
+    INDEX idx_domain_events_due (dispatched_at, next_attempt_at),
+    INDEX idx_domain_events_aggregate (aggregate_id, created_at)
+);
+
+-- Subscribers that have processed each event
+CREATE TABLE IF NOT EXISTS domain_event_consumers (
+    event_id VARCHAR(128) NOT NULL,
+    subscriber VARCHAR(50) NOT NULL COMMENT 'notifications, webhooks, sse, analytics',
+    handled_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (event_id, subscriber),
+    FOREIGN KEY (event_id) REFERENCES domain_events(id) ON DELETE CASCADE
+);
+
+-- Daily event counts (analytics subscriber)
+CREATE TABLE IF NOT EXISTS analytics_daily_events (
+    day DATE NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    count INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, event_type)
+);
+
+-- AI reply jobs: one per user message that needs an AI draft, worked by the background pool
+CREATE TABLE IF NOT EXISTS ai_jobs (
+    id VARCHAR(128) PRIMARY KEY COMMENT 'ULID',
+    kind VARCHAR(30) NOT NULL COMMENT 'negotiation_reply, public_answer',
+    item_id VARCHAR(128) NOT NULL,
+    buyer_id VARCHAR(128) NULL COMMENT 'Negotiation thread, NULL for public questions',
+    message_id VARCHAR(128) NOT NULL COMMENT 'The message being answered',
+    status VARCHAR(20) NOT NULL DEFAULT 'queued' COMMENT 'queued, running, succeeded, failed, skipped',
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL DEFAULT 5,
+    next_attempt_at TIMESTAMP(6) NOT NULL COMMENT 'Retry time, also the worker lease while running',
+    last_error TEXT,
+    result_message_id VARCHAR(128) NULL COMMENT 'AI message produced',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+    FOREIGN KEY (message_id) REFERENCES messages(id) ON DELETE CASCADE,
+    INDEX idx_ai_jobs_due (status, next_attempt_at),
+    INDEX idx_ai_jobs_message (message_id)
+);
+
+-- Item price history: every price change with where it came from
+CREATE TABLE IF NOT EXISTS item_price_history (
+    id VARCHAR(128) PRIMARY KEY COMMENT 'ULID',
+    item_id VARCHAR(128) NOT NULL,
+    old_price INT DEFAULT NULL COMMENT 'NULL for the listing price',
+    new_price INT NOT NULL,
+    source VARCHAR(20) NOT NULL COMMENT 'listing, seller_edit, negotiation, revoke, promotion',
+    changed_by VARCHAR(128) DEFAULT NULL COMMENT 'User who made the change',
+    message_id VARCHAR(128) DEFAULT NULL COMMENT 'Approved negotiation message that set the price',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+    INDEX idx_item_price_history_item (item_id, created_at)
+);
+
+
+-- Item watches: users who watch or like an item and hear about price drops
+CREATE TABLE IF NOT EXISTS item_watches (
+    item_id VARCHAR(128) NOT NULL,
+    user_id VARCHAR(128) NOT NULL,
+    watching BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Explicit watch',
+    liked BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Likes watch implicitly',
+    target_price INT DEFAULT NULL COMMENT 'Alert only at or below this price, NULL alerts on every drop',
+    notified_price INT DEFAULT NULL COMMENT 'Last price the user was alerted about',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (item_id, user_id),
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_item_watches_user (user_id, created_at)
+);
+
+-- Address book. Personal fields are encrypted by the app (AES-GCM, ADDRESS_ENCRYPTION_KEY) into
+-- sealed_fields. Only the prefecture is stored in the clear, for shipping quotes.
+CREATE TABLE IF NOT EXISTS user_addresses (
+    id VARCHAR(128) PRIMARY KEY COMMENT 'ULID',
+    user_id VARCHAR(128) NOT NULL,
+    prefecture CHAR(2) NOT NULL COMMENT 'JIS prefecture code',
+    sealed_fields TEXT NOT NULL COMMENT 'Encrypted label, name, postal code, city, lines, phone',
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_user_addresses_user (user_id, created_at)
+);
+
+-- The address chosen at purchase, copied so later edits to the address book don't change it.
+-- Shown only to the item's seller and buyer.
+CREATE TABLE IF NOT EXISTS purchase_addresses (
+    item_id VARCHAR(128) PRIMARY KEY,
+    buyer_id VARCHAR(128) NOT NULL,
+    address_id VARCHAR(128) DEFAULT NULL COMMENT 'Address book entry it was copied from',
+    prefecture CHAR(2) NOT NULL,
+    sealed_fields TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+    FOREIGN KEY (buyer_id) REFERENCES users(id)
+);
+
+-- After-sale cases on a sold item: a cancellation request the other party accepts or
+-- declines, or a dispute an admin resolves. At most one case per item is open at a time.
+-- A refunded dispute moves the item to status 'cancelled', which listings leave out.
+CREATE TABLE IF NOT EXISTS order_cases (
+    id VARCHAR(128) PRIMARY KEY COMMENT 'ULID',
+    item_id VARCHAR(128) NOT NULL,
+    kind VARCHAR(20) NOT NULL COMMENT 'cancellation, dispute',
+    status VARCHAR(20) NOT NULL DEFAULT 'open' COMMENT 'open, accepted, declined, withdrawn, resolved',
+    seller_id VARCHAR(128) NOT NULL,
+    buyer_id VARCHAR(128) NOT NULL,
+    opened_by VARCHAR(128) NOT NULL,
+    reason VARCHAR(40) NOT NULL,
+    details TEXT NOT NULL,
+    outcome VARCHAR(20) DEFAULT NULL COMMENT 'refund, complete or relist, set when the sale is settled',
+    resolution_note TEXT DEFAULT NULL COMMENT 'Admin note on a resolved dispute',
+    closed_by VARCHAR(128) DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    closed_at TIMESTAMP NULL DEFAULT NULL,
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+    INDEX idx_order_cases_item (item_id, created_at),
+    INDEX idx_order_cases_status (status, kind, created_at)
+);
+
+-- Statements and evidence on a case, from either party or an admin.
+CREATE TABLE IF NOT EXISTS order_case_messages (
+    id VARCHAR(128) PRIMARY KEY COMMENT 'ULID',
+    case_id VARCHAR(128) NOT NULL,
+    sender_id VARCHAR(128) NOT NULL,
+    content TEXT NOT NULL,
+    evidence_url TEXT DEFAULT NULL COMMENT 'Photo or document backing the message',
+    created_at TIMESTAMP(3) DEFAULT CURRENT_TIMESTAMP(3),
+    FOREIGN KEY (case_id) REFERENCES order_cases(id) ON DELETE CASCADE,
+    INDEX idx_order_case_messages_case (case_id, created_at)
+);
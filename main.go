@@ -2,57 +2,65 @@ package main
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
+	"hackathon-backend/config"
 	"hackathon-backend/controller"
 	"hackathon-backend/dao"
+	"hackathon-backend/model"
+	"hackathon-backend/pkg/fieldcrypt"
 	"hackathon-backend/pkg/gemini"
+	"hackathon-backend/pkg/notify"
+	"hackathon-backend/pkg/sse"
+	"hackathon-backend/router"
 	"hackathon-backend/usecase"
 	"log"
 	"net/http"
 	"os"
-
-	_ "github.com/go-sql-driver/mysql"
+	"os/signal"
+	"sync"
+	"syscall"
 )
 
 func main() {
-	// 1. DB Connection
-	user := os.Getenv("MYSQL_USER")
-	if user == "" {
-		user = "user"
-	}
-	pwd := os.Getenv("MYSQL_PWD")
-	if pwd == "" {
-		pwd = "password"
-	}
-	host := os.Getenv("MYSQL_HOST")
-	if host == "" {
-		host = "tcp(127.0.0.1:3306)"
-	}
-	dbName := os.Getenv("MYSQL_DATABASE")
-	if dbName == "" {
-		dbName = "hackathon_db"
-	}
-
-	dsn := fmt.Sprintf("%s:%s@%s/%s?parseTime=true&loc=Local", user, pwd, host, dbName)
-	db, err := sql.Open("mysql", dsn)
+	cfg, err := config.Load()
 	if err != nil {
-		log.Fatal(err)
+		log.Fatal("Invalid configuration:\n", err)
 	}
-	defer db.Close()
+	fmt.Printf("Configuration:\n%s", cfg)
 
-	if err := db.Ping(); err != nil {
+	// 1. DB Connection
+	db, err := dao.OpenDB(cfg.DB)
+	if err != nil {
 		log.Fatal("Failed to connect to DB:", err)
 	}
+	defer db.Close()
 	fmt.Println("Connected to Database!")
 
 	// 2. Gemini Client (API Key)
-    apiKey := os.Getenv("GEMINI_API_KEY")
-    
+	// Prompt templates: optional default override and A/B split, e.g. PROMPT_EXPERIMENT=v1:90,v2:10
+	prompts, err := gemini.NewPromptRegistry()
+	if err != nil {
+		log.Fatal("Failed to load prompt templates:", err)
+	}
+	if v := cfg.Gemini.PromptDefaultVersion; v != "" {
+		if err := prompts.SetDefault(v); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if spec := cfg.Gemini.PromptExperiment; spec != "" {
+		arms, err := gemini.ParseExperiment(spec)
+		if err == nil {
+			err = prompts.SetExperiment(arms)
+		}
+		if err != nil {
+			log.Fatal("Invalid PROMPT_EXPERIMENT:", err)
+		}
+	}
+
 	var geminiClient *gemini.Client
-	if apiKey != "" {
+	if cfg.Gemini.APIKey != "" {
 		ctx := context.Background()
-		client, err := gemini.NewClient(ctx, apiKey)
+		client, err := gemini.NewClient(ctx, cfg.Gemini.APIKey, prompts)
 		if err != nil {
 			log.Printf("Failed to init Gemini Client: %v", err)
 		} else {
@@ -63,30 +71,178 @@ func main() {
 		fmt.Println("GEMINI_API_KEY not set. Smart-Nego will be disabled.")
 	}
 
+	usecase.SetDeadlines(usecase.Deadlines{
+		Read:  cfg.Deadlines.Read,
+		Write: cfg.Deadlines.Write,
+		LLM:   cfg.Deadlines.LLM,
+	})
+
+	// Background workers (dispatchers, AI job pool) stop once the server has drained
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	runWorker := func(run func(ctx context.Context)) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			run(workerCtx)
+		}()
+	}
+
 	// 3. Dependency Injection
 	itemRepo := dao.NewItemRepository(db)
 	msgRepo := dao.NewMessageRepository(db)
-	
-	itemUsecase := usecase.NewItemUsecase(itemRepo, msgRepo, geminiClient)
+	faqRepo := dao.NewFAQRepository(db)
+	userRepo := dao.NewUserRepository(db)
+
+	// Notifications: in-app always; email / web push only when configured
+	var notifiers []notify.Notifier
+	if cfg.SMTP.Host != "" {
+		notifiers = append(notifiers, notify.NewEmailNotifier(notify.EmailConfig{
+			Host:     cfg.SMTP.Host,
+			Port:     cfg.SMTP.Port,
+			From:     cfg.SMTP.From,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+		}))
+		fmt.Println("Email notifications enabled")
+	}
+	if cfg.WebPush.VAPIDPublicKey != "" {
+		notifiers = append(notifiers, notify.NewWebPushNotifier(notify.WebPushConfig{
+			VAPIDPublicKey:  cfg.WebPush.VAPIDPublicKey,
+			VAPIDPrivateKey: cfg.WebPush.VAPIDPrivateKey,
+			Subscriber:      cfg.WebPush.Subscriber,
+		}))
+		fmt.Println("Web push notifications enabled")
+	}
+	notificationRepo := dao.NewNotificationRepository(db)
+	notificationUsecase := usecase.NewNotificationUsecase(notificationRepo, userRepo, notifiers...)
+	notificationController := controller.NewNotificationController(notificationUsecase)
+
+	// Webhooks: deliveries are queued in the outbox table and sent by a background dispatcher
+	webhookRepo := dao.NewWebhookRepository(db)
+	webhookUsecase := usecase.NewWebhookUsecase(webhookRepo)
+	webhookController := controller.NewWebhookController(webhookUsecase)
+	runWorker(webhookUsecase.RunDispatcher)
+
+	streamUsecase := usecase.NewStreamUsecase(sse.NewHub())
+	streamController := controller.NewStreamController(streamUsecase)
+
+	analyticsRepo := dao.NewAnalyticsRepository(db)
+	analyticsUsecase := usecase.NewAnalyticsUsecase(analyticsRepo)
+	analyticsController := controller.NewAnalyticsController(analyticsUsecase)
+
+	// Watches and likes: price drops reach them through the item.price_changed event
+	watchUsecase := usecase.NewWatchUsecase(dao.NewWatchRepository(db), itemRepo, notificationUsecase)
+	watchController := controller.NewWatchController(watchUsecase)
+
+	// Domain events: written to the outbox with each state change, then delivered to subscribers
+	eventDispatcher := usecase.NewEventDispatcher(dao.NewEventRepository(db))
+	eventDispatcher.Subscribe("notifications", notificationUsecase.HandleEvent)
+	eventDispatcher.Subscribe("webhooks", webhookUsecase.HandleEvent)
+	eventDispatcher.Subscribe("sse", streamUsecase.HandleEvent)
+	eventDispatcher.Subscribe("analytics", analyticsUsecase.HandleEvent)
+	eventDispatcher.Subscribe("price_alerts", watchUsecase.HandleEvent, model.EventPriceChanged)
+	runWorker(eventDispatcher.Run)
+
+	// Address book: personal fields are encrypted at rest with ADDRESS_ENCRYPTION_KEY
+	var addressCipher *fieldcrypt.Cipher
+	if cfg.Addresses.EncryptionKey != "" {
+		addressCipher, err = fieldcrypt.NewFromBase64(cfg.Addresses.EncryptionKey)
+		if err != nil {
+			log.Fatal("Invalid ADDRESS_ENCRYPTION_KEY:", err)
+		}
+		fmt.Println("Address book enabled")
+	} else {
+		fmt.Println("ADDRESS_ENCRYPTION_KEY not set. The address book will be disabled.")
+	}
+	txManager := dao.NewTxManager(db)
+	addressUsecase := usecase.NewAddressUsecase(dao.NewAddressRepository(db), itemRepo, txManager, addressCipher)
+	addressController := controller.NewAddressController(addressUsecase)
+
+	// AI replies: queued with the buyer's message and generated by a pool of workers
+	aiJobRepo := dao.NewAIJobRepository(db)
+	aiJobs := usecase.NewAIJobQueue(aiJobRepo)
+	categoryRepo := dao.NewCategoryRepository(db)
+	itemUsecase := usecase.NewItemUsecase(itemRepo, msgRepo, faqRepo, userRepo, dao.NewPriceHistoryRepository(db), categoryRepo, addressUsecase, aiJobRepo, txManager, eventDispatcher, aiJobs, geminiClient)
 	itemController := controller.NewItemController(itemUsecase)
+	aiJobs.Handle(model.JobNegotiationReply, itemUsecase.ProcessNegotiationJob)
+	aiJobs.Handle(model.JobPublicAnswer, itemUsecase.ProcessPublicAnswerJob)
+	runWorker(func(ctx context.Context) { aiJobs.Run(ctx, cfg.AIWorkers) })
+
+	// Cancellations and disputes: admins (ADMIN_USER_IDS) resolve disputes
+	orderCaseUsecase := usecase.NewOrderCaseUsecase(dao.NewOrderCaseRepository(db), itemRepo, txManager, eventDispatcher, cfg.Admin.UserIDs)
+	orderCaseController := controller.NewOrderCaseController(orderCaseUsecase)
+
+	categoryController := controller.NewCategoryController(usecase.NewCategoryUsecase(categoryRepo))
 
-	userRepo := dao.NewUserRepository(db)
 	userUsecase := usecase.NewUserUsecase(userRepo)
 	userController := controller.NewUserController(userUsecase)
 
+	negotiationRepo := dao.NewNegotiationRepository(db)
+	negotiationUsecase := usecase.NewNegotiationUsecase(negotiationRepo)
+	negotiationController := controller.NewNegotiationController(negotiationUsecase)
+
 	// 4. Routing
-	http.HandleFunc("/items", itemController.HandleItems)
-	http.HandleFunc("/items/", itemController.HandleItemDetail) // Handles /buy and /messages
-    http.HandleFunc("/messages/", itemController.HandleMessages) // Handles /messages/{id}/approve
-	http.HandleFunc("/register", userController.Register)
+	handler := router.New(router.Controllers{
+		Item:         itemController,
+		User:         userController,
+		Negotiation:  negotiationController,
+		Notification: notificationController,
+		Webhook:      webhookController,
+		Stream:       streamController,
+		Analytics:    analyticsController,
+		Watch:        watchController,
+		Category:     categoryController,
+		Address:      addressController,
+		OrderCase:    orderCaseController,
+	}, router.Config{AllowedOrigins: cfg.CORS.AllowedOrigins})
 
 	// 5. Start Server
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	server := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		ReadTimeout:       cfg.HTTP.ReadTimeout,
+		WriteTimeout:      cfg.HTTP.WriteTimeout, // SSE streams lift it per connection
+		IdleTimeout:       cfg.HTTP.IdleTimeout,
 	}
-	fmt.Printf("Server starting on port %s...\n", port)
-	if err := http.ListenAndServe(":"+port, nil); err != nil {
+	server.RegisterOnShutdown(streamUsecase.Close) // SSE streams would never finish on their own
+
+	serveErr := make(chan error, 1)
+	go func() {
+		fmt.Printf("Server starting on port %s...\n", cfg.Port)
+		serveErr <- server.ListenAndServe()
+	}()
+
+	// 6. Graceful shutdown: stop accepting, drain in-flight requests, then stop the workers
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case err := <-serveErr:
 		log.Fatal(err)
+	case sig := <-stop:
+		fmt.Printf("Received %s, shutting down...\n", sig)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("HTTP server did not drain: %v", err)
+	}
+
+	stopWorkers()
+	drained := make(chan struct{})
+	go func() {
+		workers.Wait()
+		// The event dispatcher has stopped, so no new sends start; finish those in flight
+		notificationUsecase.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		fmt.Println("Shutdown complete")
+	case <-shutdownCtx.Done():
+		// Unfinished jobs and deliveries are picked up again once their lease expires
+		log.Println("Background workers did not stop in time")
 	}
 }
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// Address is a shipping address in a user's address book.
+type Address struct {
+	ID         string    `json:"id"`
+	UserID     string    `json:"user_id"`
+	Label      string    `json:"label,omitempty"` // e.g. 自宅
+	Name       string    `json:"name"`            // Recipient
+	PostalCode string    `json:"postal_code"`     // 123-4567
+	Prefecture string    `json:"prefecture"`      // JIS code, "01" to "47"
+	City       string    `json:"city"`
+	Line1      string    `json:"line1"`
+	Line2      string    `json:"line2,omitempty"` // Building, room
+	Phone      string    `json:"phone"`
+	IsDefault  bool      `json:"is_default"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// SealedAddress is an address as stored: everything but the prefecture, which
+// shipping quotes need, is encrypted in SealedFields.
+type SealedAddress struct {
+	ID           string
+	UserID       string
+	Prefecture   string
+	SealedFields string
+	IsDefault    bool
+	CreatedAt    time.Time
+}
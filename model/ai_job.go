@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// AI job kinds
+const (
+	JobNegotiationReply = "negotiation_reply" // Smart-Nego draft for a buyer's thread message
+	JobPublicAnswer     = "public_answer"     // Draft answer to a public question
+)
+
+// AI job statuses
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"  // Gave up after the last retry
+	JobSkipped   = "skipped" // Superseded by a newer buyer message before it ran
+)
+
+// AIJob is a queued AI reply generation, triggered by one user message.
+type AIJob struct {
+	ID              string    `json:"id"`
+	Kind            string    `json:"kind"`
+	ItemID          string    `json:"item_id"`
+	BuyerID         string    `json:"buyer_id,omitempty"`
+	MessageID       string    `json:"message_id"` // The message being answered
+	Status          string    `json:"status"`
+	Attempts        int       `json:"attempts"`
+	MaxAttempts     int       `json:"max_attempts"`
+	NextAttemptAt   time.Time `json:"next_attempt_at"`
+	LastError       string    `json:"last_error,omitempty"`
+	ResultMessageID string    `json:"result_message_id,omitempty"` // AI message produced
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
@@ -0,0 +1,8 @@
+package model
+
+// DailyEventCount is how many domain events of one type happened on one day.
+type DailyEventCount struct {
+	Day       string `json:"day"` // YYYY-MM-DD
+	EventType string `json:"event_type"`
+	Count     int    `json:"count"`
+}
@@ -0,0 +1,39 @@
+package model
+
+// Item conditions, best first.
+const (
+	ConditionNew     = "new"
+	ConditionLikeNew = "like_new"
+	ConditionGood    = "good"
+	ConditionFair    = "fair"
+	ConditionPoor    = "poor"
+)
+
+var ItemConditions = []string{ConditionNew, ConditionLikeNew, ConditionGood, ConditionFair, ConditionPoor}
+
+// Category attribute types.
+const (
+	AttributeText   = "text"
+	AttributeNumber = "number"
+	AttributeSelect = "select" // One of Options
+)
+
+// AttributeSpec describes one category-specific item attribute.
+type AttributeSpec struct {
+	Key      string   `json:"key"`
+	Label    string   `json:"label"`
+	Type     string   `json:"type"`
+	Options  []string `json:"options,omitempty"`
+	Required bool     `json:"required,omitempty"`
+}
+
+// Category is a node of the category tree. Attributes are the category's own
+// attribute specs; items also get those of every ancestor.
+type Category struct {
+	ID         string          `json:"id"`
+	ParentID   *string         `json:"parent_id"`
+	Name       string          `json:"name"`
+	Attributes []AttributeSpec `json:"attributes"`
+	SortOrder  int             `json:"-"`
+	Children   []*Category     `json:"children,omitempty"`
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Domain event types. Webhooks expose the same names.
+const (
+	EventItemCreated    = "item.created"
+	EventItemSold       = "item.sold"
+	EventPriceChanged   = "item.price_changed"
+	EventMessageCreated = "message.created"
+	EventDraftPending   = "draft.pending"
+	EventDraftApproved  = "draft.approved"
+	EventCaseOpened     = "order.case_opened"
+	EventCaseClosed     = "order.case_closed"
+)
+
+// DomainEvent is a row of the event outbox. It is written in the same
+// transaction as the state change it describes and dispatched afterwards.
+type DomainEvent struct {
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	AggregateID   string          `json:"aggregate_id"` // Item the event is about
+	Payload       json.RawMessage `json:"payload"`
+	Attempts      int             `json:"attempts"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	LastError     string          `json:"last_error,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	DispatchedAt  *time.Time      `json:"dispatched_at,omitempty"`
+}
+
+// ItemEvent is the payload of item.* events.
+type ItemEvent struct {
+	Item Item `json:"item"`
+	// item.sold: the other buyers who had a negotiation thread on the item
+	OtherBuyerIDs []string `json:"other_buyer_ids,omitempty"`
+}
+
+// PriceChangedEvent is the payload of item.price_changed.
+type PriceChangedEvent struct {
+	Item     Item   `json:"item"` // After the change
+	OldPrice int    `json:"old_price"`
+	NewPrice int    `json:"new_price"`
+	Source   string `json:"source"` // PriceSource*
+}
+
+// CaseEvent is the payload of order.case_* events.
+type CaseEvent struct {
+	Case OrderCase `json:"case"`
+	Item Item      `json:"item"` // After the change; back on sale when the sale was reversed
+}
+
+// MessageEvent is the payload of message.* and draft.* events.
+type MessageEvent struct {
+	ItemID   string `json:"item_id"`
+	ItemName string `json:"item_name"`
+	SellerID string `json:"seller_id"`
+	// CounterpartID is the buyer side of the conversation: the thread buyer, or
+	// the asker of a public question. It may see the message only once approved.
+	CounterpartID string  `json:"counterpart_id,omitempty"`
+	Message       Message `json:"message"`
+}
+
+// Audience lists the users allowed to see the message right now.
+func (e *MessageEvent) Audience() []string {
+	users := []string{e.SellerID}
+	if e.Message.IsApproved && e.CounterpartID != "" && e.CounterpartID != e.SellerID {
+		users = append(users, e.CounterpartID)
+	}
+	return users
+}
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// ItemFAQ is an approved public Q&A pair, shown on the listing and fed to Smart-Nego.
+type ItemFAQ struct {
+	ID                string    `json:"id"`
+	ItemID            string    `json:"item_id"`
+	QuestionMessageID string    `json:"question_message_id"`
+	AnswerMessageID   string    `json:"answer_message_id"`
+	Question          string    `json:"question"`
+	Answer            string    `json:"answer"`
+	CreatedAt         time.Time `json:"created_at"`
+}
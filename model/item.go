@@ -2,6 +2,14 @@ package model
 
 import "time"
 
+// Item statuses
+const (
+	ItemStatusOnSale    = "on_sale"
+	ItemStatusSold      = "sold"
+	ItemStatusCancelled = "cancelled" // Sale refunded after a dispute: off sale and out of listings, kept for the order's history
+	ItemStatusDeleted   = "deleted"
+)
+
 type Item struct {
 	ID                   string    `json:"id"`
 	Name                 string    `json:"name"`
@@ -9,11 +17,36 @@ type Item struct {
 	Description          string    `json:"description"`
 	UserID               string    `json:"user_id"`
 	BuyerID              *string   `json:"buyer_id,omitempty"` // Nullable
-	Status               string `json:"status"` // on_sale, sold
+	Status               string `json:"status"` // on_sale, sold, cancelled, deleted
 	ViewsCount           int       `json:"views_count"`
 	AINegotiationEnabled bool      `json:"ai_negotiation_enabled"`
 	MinPrice             *int   `json:"min_price"`
     ImageURL             string `json:"image_url"`
     InitialPrice         int    `json:"initial_price"`
+	AutoApproveAnswers   bool   `json:"auto_approve_answers"`   // Publish AI answers to questions without review
+	AutoApproveMinPrice  *int   `json:"auto_approve_min_price"` // Publish AI ACCEPT/COUNTER drafts at or above this price without review
+	PromptVersion        *string `json:"prompt_version,omitempty"` // Pins a Smart-Nego prompt version (operator setting)
+	Version              int     `json:"version"`                  // Bumped on every update; the ETag
+	ItemDetails                  // Category, brand, condition and category attributes
+	ShippingOptions              // Method, payer, origin, handling time and size
 	CreatedAt            time.Time `json:"created_at"`
+	SoldAt               *time.Time `json:"sold_at,omitempty"`
+	ShippingFee          *int       `json:"shipping_fee,omitempty"` // Set at purchase: shipping charged to the buyer on top of Price
+}
+
+// ItemDetails classifies an item. Attribute values are strings whatever their type.
+type ItemDetails struct {
+	CategoryID *string           `json:"category_id"`
+	Brand      string            `json:"brand,omitempty"`
+	Condition  string            `json:"condition,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// ItemFilter narrows an item listing. Zero values don't filter.
+type ItemFilter struct {
+	CategoryID  string
+	CategoryIDs []string // Set by the usecase: CategoryID and its subcategories
+	Brand       string
+	Conditions  []string
+	Attributes  map[string]string
 }
@@ -0,0 +1,56 @@
+package model
+
+import "encoding/json"
+
+// ItemPatch is a partial listing update (PATCH /items/{id}): absent fields are
+// left as they are.
+type ItemPatch struct {
+	Name                 *string            `json:"name"`
+	Price                *int               `json:"price"`
+	Description          *string            `json:"description"`
+	AINegotiationEnabled *bool              `json:"ai_negotiation_enabled"`
+	MinPrice             NullableInt        `json:"min_price"` // null removes the floor
+	ImageURL             *string            `json:"image_url"` // "" removes the image
+	AutoApproveAnswers   *bool              `json:"auto_approve_answers"`
+	AutoApproveMinPrice  NullableInt        `json:"auto_approve_min_price"` // null turns price auto-approval off
+	ResetInitialPrice    bool               `json:"reset_initial_price"`    // Start the discount history over at the new price
+	CategoryID           NullableString     `json:"category_id"`            // null removes the category
+	Brand                *string            `json:"brand"`
+	Condition            *string            `json:"condition"`
+	Attributes           *map[string]string `json:"attributes"`      // Replaces every attribute
+	ShippingMethod       *string            `json:"shipping_method"` // "" with the other shipping fields removes shipping
+	ShippingPayer        *string            `json:"shipping_payer"`
+	ShipFrom             *string            `json:"ship_from"`
+	DaysToShip           *string            `json:"days_to_ship"`
+	SizeClass            *string            `json:"size_class"`
+}
+
+// Empty reports whether the patch changes nothing.
+func (p ItemPatch) Empty() bool {
+	return p.Name == nil && p.Price == nil && p.Description == nil && p.AINegotiationEnabled == nil &&
+		!p.MinPrice.Set && p.ImageURL == nil && p.AutoApproveAnswers == nil && !p.AutoApproveMinPrice.Set &&
+		!p.ResetInitialPrice && !p.CategoryID.Set && p.Brand == nil && p.Condition == nil && p.Attributes == nil &&
+		p.ShippingMethod == nil && p.ShippingPayer == nil && p.ShipFrom == nil && p.DaysToShip == nil && p.SizeClass == nil
+}
+
+// NullableInt tells an absent JSON field (Set false) from an explicit null (Set, Value nil).
+type NullableInt struct {
+	Set   bool
+	Value *int
+}
+
+func (n *NullableInt) UnmarshalJSON(b []byte) error {
+	n.Set = true
+	return json.Unmarshal(b, &n.Value)
+}
+
+// NullableString tells an absent JSON field (Set false) from an explicit null (Set, Value nil).
+type NullableString struct {
+	Set   bool
+	Value *string
+}
+
+func (n *NullableString) UnmarshalJSON(b []byte) error {
+	n.Set = true
+	return json.Unmarshal(b, &n.Value)
+}
@@ -5,23 +5,53 @@ import "time"
 type Message struct {
 	ID             string    `json:"id"`
 	ItemID         string    `json:"item_id"`
+	BuyerID        string    `json:"buyer_id,omitempty"` // Thread key: the buyer this conversation is with (empty for public)
+	Channel        string    `json:"channel"`            // negotiation or public
+	ReplyToID      string    `json:"reply_to_id,omitempty"` // Public answers point at the question they answer
 	SenderID       string    `json:"sender_id"`
 	SenderName     string    `json:"sender_name"`
 	Content        string    `json:"content"`
 	IsAIResponse   bool      `json:"is_ai_response"`
 	IsApproved     bool      `json:"is_approved"`
 	AIReasoning    string    `json:"ai_reasoning,omitempty"` // Derived from logs for sellers
+	AIDecision     string    `json:"ai_decision,omitempty"`    // Seller-only, from the linked negotiation log
+	DetectedPrice  *int      `json:"detected_price,omitempty"` // Seller-only, price the buyer proposed
+	CounterPrice   *int      `json:"counter_price,omitempty"`  // Seller-only, AI counter offer
 	SuggestedPrice *int      `json:"suggested_price,omitempty"`
 	CreatedAt      time.Time `json:"created_at"`
 }
 
+// Message channels: private per-buyer negotiation threads, and the item's public Q&A.
+const (
+	ChannelNegotiation = "negotiation"
+	ChannelPublic      = "public"
+)
+
+// Thread summarises one buyer's negotiation conversation on an item (seller inbox).
+// Counts are from the point of view of the user who listed the threads.
+type Thread struct {
+	ItemID        string    `json:"item_id"`
+	ItemName      string    `json:"item_name"`
+	BuyerID       string    `json:"buyer_id"`
+	BuyerName     string    `json:"buyer_name"`
+	MessageCount  int       `json:"message_count"`
+	LastMessage   string    `json:"last_message"`
+	LastMessageAt time.Time `json:"last_message_at"`
+	UnreadCount   int       `json:"unread_count"`   // Messages from the other party after the viewer's read marker
+	PendingDrafts int       `json:"pending_drafts"` // Seller only: AI drafts waiting for approval
+}
+
 type NegotiationLog struct {
 	ID            string    `json:"id"`
 	ItemID        string    `json:"item_id"`
 	UserID        string    `json:"user_id"`
+	MessageID     string    `json:"message_id"` // AI message this decision produced
 	ProposedPrice int       `json:"proposed_price"`
 	AIDecision    string    `json:"ai_decision"` // ACCEPT, REJECT, COUNTER, ANSWER
 	CounterPrice  int       `json:"counter_price"`
 	AIReasoning   string    `json:"ai_reasoning"`
+	AutoApproved  bool      `json:"auto_approved"` // Draft was published without seller review
+	PromptVersion string    `json:"prompt_version"`
+	Outcome       string    `json:"outcome"` // pending, approved, rejected, regenerated
 	LogTime       time.Time `json:"log_time"`
 }
@@ -0,0 +1,76 @@
+package model
+
+// PromptVersionReport compares negotiation outcomes between Smart-Nego prompt versions.
+type PromptVersionReport struct {
+	PromptVersion      string  `json:"prompt_version"`
+	Decisions          int     `json:"decisions"`       // All logged AI decisions
+	PriceDecisions     int     `json:"price_decisions"` // Decisions other than ANSWER
+	Accepts            int     `json:"accepts"`
+	AcceptanceRate     float64 `json:"acceptance_rate"` // Accepts / PriceDecisions
+	SoldCount          int     `json:"sold_count"`      // Conversations that ended in a sale to that buyer
+	AvgFinalPrice      float64 `json:"avg_final_price"`
+	AvgFinalPriceRatio float64 `json:"avg_final_price_ratio"` // Final price / initial price
+}
+
+// What the seller did with an AI draft (negotiation_logs.outcome).
+const (
+	OutcomePending     = "pending"
+	OutcomeApproved    = "approved"
+	OutcomeRejected    = "rejected"
+	OutcomeRegenerated = "regenerated"
+)
+
+// SellerNegotiationStats is the seller dashboard payload: aggregate metrics plus one entry per item.
+type SellerNegotiationStats struct {
+	SellerID string                 `json:"seller_id"`
+	Totals   NegotiationStats       `json:"totals"`
+	Items    []ItemNegotiationStats `json:"items"`
+}
+
+type NegotiationStats struct {
+	Offers                    int            `json:"offers"`    // Buyer messages with a price
+	Decisions                 map[string]int `json:"decisions"` // ACCEPT, REJECT, COUNTER, ANSWER
+	SoldCount                 int            `json:"sold_count"`
+	AvgDiscountRate           *float64       `json:"avg_discount_rate,omitempty"`      // (initial - final) / initial over sold items
+	AvgTimeToSaleHours        *float64       `json:"avg_time_to_sale_hours,omitempty"` // Listing to purchase over sold items
+	Drafts                    DraftStats     `json:"drafts"`
+	ProposedPriceDistribution []PriceBucket  `json:"proposed_price_distribution"` // Buyer offers relative to initial price
+}
+
+type ItemNegotiationStats struct {
+	ItemID       string `json:"item_id"`
+	Name         string `json:"name"`
+	Status       string `json:"status"`
+	InitialPrice int    `json:"initial_price"`
+	Price        int    `json:"price"`
+	NegotiationStats
+}
+
+// DraftStats counts what happened to AI drafts.
+type DraftStats struct {
+	Total            int     `json:"total"`
+	Approved         int     `json:"approved"`
+	AutoApproved     int     `json:"auto_approved"` // Subset of Approved
+	Rejected         int     `json:"rejected"`
+	Regenerated      int     `json:"regenerated"`
+	Pending          int     `json:"pending"`
+	ApprovalRate     float64 `json:"approval_rate"`
+	RejectionRate    float64 `json:"rejection_rate"`
+	RegenerationRate float64 `json:"regeneration_rate"`
+}
+
+type PriceBucket struct {
+	Label    string  `json:"label"`     // e.g. "70-80%"
+	MinRatio float64 `json:"min_ratio"` // Inclusive, proposed / initial price
+	MaxRatio float64 `json:"max_ratio"` // Exclusive; 0 means unbounded
+	Count    int     `json:"count"`
+}
+
+// NegotiationLogStat is the slice of a negotiation log the stats are computed from.
+type NegotiationLogStat struct {
+	ItemID        string
+	ProposedPrice int
+	AIDecision    string
+	Outcome       string
+	AutoApproved  bool
+}
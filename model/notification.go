@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+// Notification events
+const (
+	NotifyDraftPending  = "draft_pending"  // Seller: an AI draft waits for approval
+	NotifyReplyApproved = "reply_approved" // Buyer: a seller/AI reply was published
+	NotifyItemSold      = "item_sold"      // Seller and other negotiating buyers: the item sold
+	NotifyPriceDrop     = "price_drop"     // Watchers and likers: the price dropped or reached their target
+	NotifyOrderCase     = "order_case"     // Seller and buyer: a cancellation request or dispute was opened or closed
+)
+
+// Delivery channels (mirrors pkg/notify)
+const (
+	NotifyChannelInApp   = "in_app"
+	NotifyChannelEmail   = "email"
+	NotifyChannelWebPush = "web_push"
+)
+
+type Notification struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Type      string    `json:"type"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	ItemID    string    `json:"item_id,omitempty"`
+	MessageID string    `json:"message_id,omitempty"`
+	IsRead    bool      `json:"is_read"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NotificationPreference turns one delivery channel on or off for one event type.
+// Missing rows fall back to the defaults: in-app on, email and web push off.
+type NotificationPreference struct {
+	UserID    string `json:"user_id"`
+	EventType string `json:"event_type"`
+	Channel   string `json:"channel"`
+	Enabled   bool   `json:"enabled"`
+}
+
+type PushSubscription struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Endpoint  string    `json:"endpoint"`
+	P256dh    string    `json:"p256dh"`
+	Auth      string    `json:"auth"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,71 @@
+package model
+
+import "time"
+
+// Case kinds
+const (
+	CaseCancellation = "cancellation" // One party asks to call the sale off; the other accepts or declines
+	CaseDispute      = "dispute"      // Something went wrong with the order; an admin decides
+)
+
+// Case statuses
+const (
+	CaseOpen      = "open"
+	CaseAccepted  = "accepted"  // Cancellation agreed by the other party
+	CaseDeclined  = "declined"  // Cancellation refused by the other party
+	CaseWithdrawn = "withdrawn" // Closed by whoever opened it
+	CaseResolved  = "resolved"  // Dispute settled by an admin
+)
+
+// Outcomes of a settled case
+const (
+	OutcomeRefund   = "refund"   // Sale reversed and the buyer refunded; the item is marked cancelled
+	OutcomeComplete = "complete" // Sale stands
+	OutcomeRelist   = "relist"   // Sale reversed; the item goes back on sale
+)
+
+var CancellationReasons = []string{"changed_mind", "cannot_ship", "wrong_price", "other"}
+
+var DisputeReasons = []string{"not_received", "not_as_described", "damaged", "unresponsive", "other"}
+
+// OrderCase is a cancellation request or dispute on a sold item.
+type OrderCase struct {
+	ID             string        `json:"id"`
+	ItemID         string        `json:"item_id"`
+	Kind           string        `json:"kind"`
+	Status         string        `json:"status"`
+	SellerID       string        `json:"seller_id"`
+	BuyerID        string        `json:"buyer_id"`
+	OpenedBy       string        `json:"opened_by"`
+	Reason         string        `json:"reason"`
+	Details        string        `json:"details"`
+	Outcome        string        `json:"outcome,omitempty"`
+	ResolutionNote string        `json:"resolution_note,omitempty"`
+	ClosedBy       string        `json:"closed_by,omitempty"`
+	CreatedAt      time.Time     `json:"created_at"`
+	ClosedAt       *time.Time    `json:"closed_at,omitempty"`
+	Messages       []CaseMessage `json:"messages,omitempty"` // Only when a single case is fetched
+}
+
+// Counterpart is the party who did not open the case.
+func (c *OrderCase) Counterpart() string {
+	if c.OpenedBy == c.SellerID {
+		return c.BuyerID
+	}
+	return c.SellerID
+}
+
+// IsParty reports whether userID is the seller or the buyer.
+func (c *OrderCase) IsParty(userID string) bool {
+	return userID == c.SellerID || userID == c.BuyerID
+}
+
+// CaseMessage is a statement on a case, optionally with evidence.
+type CaseMessage struct {
+	ID          string    `json:"id"`
+	CaseID      string    `json:"case_id"`
+	SenderID    string    `json:"sender_id"`
+	Content     string    `json:"content"`
+	EvidenceURL string    `json:"evidence_url,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// Where a price change came from.
+const (
+	PriceSourceListing     = "listing"     // The price the item was listed at
+	PriceSourceSellerEdit  = "seller_edit" // The seller edited the listing
+	PriceSourceNegotiation = "negotiation" // An approved Smart-Nego draft moved the price
+	PriceSourceRevoke      = "revoke"      // The seller revoked that draft and the price went back
+	PriceSourcePromotion   = "promotion"   // A promotional price change
+)
+
+// PriceChange is one entry of an item's price history.
+type PriceChange struct {
+	ID        string    `json:"id"`
+	ItemID    string    `json:"item_id"`
+	OldPrice  *int      `json:"old_price"` // Nil for the listing price
+	NewPrice  int       `json:"new_price"`
+	Source    string    `json:"source"`
+	ChangedBy string    `json:"changed_by,omitempty"` // User who made the change
+	MessageID *string   `json:"message_id,omitempty"` // Negotiation: the approved message carrying the price
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PriceHistory is an item's price changes, oldest first, with the overall drop.
+type PriceHistory struct {
+	ItemID        string        `json:"item_id"`
+	InitialPrice  int           `json:"initial_price"`
+	CurrentPrice  int           `json:"current_price"`
+	LowestPrice   int           `json:"lowest_price"`
+	DropFromFirst int           `json:"drop_from_first"` // First recorded price minus current price
+	Changes       []PriceChange `json:"changes"`
+}
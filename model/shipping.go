@@ -0,0 +1,30 @@
+package model
+
+// ShippingOptions are the seller's shipping settings on a listing. Values come
+// from pkg/shipping; all are empty for listings without shipping set.
+type ShippingOptions struct {
+	ShippingMethod string `json:"shipping_method,omitempty"` // rakuraku, yuyu, standard
+	ShippingPayer  string `json:"shipping_payer,omitempty"`  // seller (included in the price) or buyer
+	ShipFrom       string `json:"ship_from,omitempty"`       // JIS prefecture code, "01" to "47"
+	DaysToShip     string `json:"days_to_ship,omitempty"`    // 1-2, 2-3, 4-7
+	SizeClass      string `json:"size_class,omitempty"`      // small, compact, 60 ... 160
+}
+
+// ShippingRate is what a carrier charges for one parcel.
+type ShippingRate struct {
+	ShippingMethod string `json:"shipping_method"`
+	SizeClass      string `json:"size_class"`
+	ShipFrom       string `json:"ship_from"`
+	ShipTo         string `json:"ship_to"`
+	ShippingCost   int    `json:"shipping_cost"`
+}
+
+// ShippingQuote is what a buyer pays for an item sent to a prefecture.
+type ShippingQuote struct {
+	ShippingRate
+	ShippingPayer string `json:"shipping_payer"`
+	DaysToShip    string `json:"days_to_ship"`
+	ItemPrice     int    `json:"item_price"`
+	ShippingFee   int    `json:"shipping_fee"` // The buyer's share: ShippingCost if the buyer pays, else 0
+	Total         int    `json:"total"`
+}
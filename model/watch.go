@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// ItemWatch is a user's interest in an item: an explicit watch, a like, or
+// both. Either one subscribes the user to price-drop alerts.
+type ItemWatch struct {
+	ItemID        string    `json:"item_id"`
+	UserID        string    `json:"user_id"`
+	Watching      bool      `json:"watching"`
+	Liked         bool      `json:"liked"`
+	TargetPrice   *int      `json:"target_price"`             // Alert only at or below this price; nil alerts on every drop
+	NotifiedPrice *int      `json:"notified_price,omitempty"` // Last price the user was alerted about
+	CreatedAt     time.Time `json:"created_at"`
+	Item          *Item     `json:"item,omitempty"` // Set when listing a user's watches
+}
@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+// Delivery statuses
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed" // Gave up after the last retry
+)
+
+type WebhookEndpoint struct {
+	ID         string    `json:"id"`
+	UserID     string    `json:"user_id"`
+	URL        string    `json:"url"`
+	Secret     string    `json:"secret,omitempty"` // HMAC key; only returned to the owner
+	EventTypes []string  `json:"event_types"`      // Empty means every event
+	IsActive   bool      `json:"is_active"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// WebhookDelivery is one event queued for one endpoint (the outbox row).
+type WebhookDelivery struct {
+	ID            string           `json:"id"`
+	EndpointID    string           `json:"endpoint_id"`
+	EventID       string           `json:"event_id"`
+	EventType     string           `json:"event_type"`
+	Payload       string           `json:"payload"`
+	Status        string           `json:"status"`
+	Attempts      int              `json:"attempts"`
+	NextAttemptAt time.Time        `json:"next_attempt_at"`
+	LastError     string           `json:"last_error,omitempty"`
+	CreatedAt     time.Time        `json:"created_at"`
+	DeliveredAt   *time.Time       `json:"delivered_at,omitempty"`
+	AttemptLog    []WebhookAttempt `json:"attempt_log,omitempty"`
+
+	// Joined from the endpoint when dispatching
+	URL    string `json:"-"`
+	Secret string `json:"-"`
+}
+
+// WebhookAttempt records one HTTP attempt of a delivery.
+type WebhookAttempt struct {
+	ID          string    `json:"id"`
+	DeliveryID  string    `json:"delivery_id"`
+	StatusCode  int       `json:"status_code"` // 0 when no response was received
+	Error       string    `json:"error,omitempty"`
+	DurationMs  int       `json:"duration_ms"`
+	AttemptedAt time.Time `json:"attempted_at"`
+}
@@ -0,0 +1,77 @@
+// Package fieldcrypt encrypts individual database values with AES-256-GCM.
+// Each value is bound to a context string (such as its row ID), so a
+// ciphertext copied to another row fails to open.
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// KeySize is the key length in bytes.
+const KeySize = 32
+
+// keyVersion prefixes every ciphertext so keys can be rotated later.
+const keyVersion = "v1:"
+
+var ErrCorrupt = errors.New("fieldcrypt: ciphertext is corrupt or was sealed for another value")
+
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// New takes a 32-byte key.
+func New(key []byte) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("fieldcrypt: key must be %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// NewFromBase64 takes the key in standard base64, as configured.
+func NewFromBase64(key string) (*Cipher, error) {
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, fmt.Errorf("fieldcrypt: key is not base64: %w", err)
+	}
+	return New(raw)
+}
+
+// Seal encrypts plaintext for storage, bound to context.
+func (c *Cipher) Seal(plaintext []byte, context string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := c.aead.Seal(nonce, nonce, plaintext, []byte(context))
+	return keyVersion + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a value from Seal with the same context.
+func (c *Cipher) Open(ciphertext string, context string) ([]byte, error) {
+	if len(ciphertext) < len(keyVersion) || ciphertext[:len(keyVersion)] != keyVersion {
+		return nil, ErrCorrupt
+	}
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext[len(keyVersion):])
+	if err != nil || len(sealed) < c.aead.NonceSize() {
+		return nil, ErrCorrupt
+	}
+	nonce, box := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, box, []byte(context))
+	if err != nil {
+		return nil, ErrCorrupt
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,100 @@
+package fieldcrypt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testCipher(t *testing.T) *Cipher {
+	t.Helper()
+	c, err := New(bytes.Repeat([]byte{7}, KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestRoundTrip(t *testing.T) {
+	c := testCipher(t)
+	for _, plaintext := range []string{"", "東京都渋谷区", `{"name":"Taro","phone":"09012345678"}`} {
+		sealed, err := c.Seal([]byte(plaintext), "address:01H")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(sealed, keyVersion) {
+			t.Errorf("sealed value %q has no key version", sealed)
+		}
+		if plaintext != "" && strings.Contains(sealed, plaintext) {
+			t.Errorf("sealed value contains the plaintext")
+		}
+		opened, err := c.Open(sealed, "address:01H")
+		if err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+		if string(opened) != plaintext {
+			t.Errorf("opened %q, want %q", opened, plaintext)
+		}
+	}
+}
+
+func TestSealUsesFreshNonces(t *testing.T) {
+	c := testCipher(t)
+	a, _ := c.Seal([]byte("same"), "ctx")
+	b, _ := c.Seal([]byte("same"), "ctx")
+	if a == b {
+		t.Fatal("sealing the same value twice gave the same ciphertext")
+	}
+}
+
+func TestOpenRejects(t *testing.T) {
+	c := testCipher(t)
+	sealed, err := c.Seal([]byte("secret"), "address:A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := New(bytes.Repeat([]byte{8}, KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := base64.StdEncoding.DecodeString(sealed[len(keyVersion):])
+	raw[len(raw)-1] ^= 1
+	tampered := keyVersion + base64.StdEncoding.EncodeToString(raw)
+
+	tests := []struct {
+		name       string
+		cipher     *Cipher
+		ciphertext string
+		context    string
+	}{
+		{name: "other context", cipher: c, ciphertext: sealed, context: "address:B"},
+		{name: "purchase context", cipher: c, ciphertext: sealed, context: "purchase:A"},
+		{name: "other key", cipher: other, ciphertext: sealed, context: "address:A"},
+		{name: "tampered", cipher: c, ciphertext: tampered, context: "address:A"},
+		{name: "no version", cipher: c, ciphertext: sealed[len(keyVersion):], context: "address:A"},
+		{name: "not base64", cipher: c, ciphertext: keyVersion + "***", context: "address:A"},
+		{name: "too short", cipher: c, ciphertext: keyVersion + "AAAA", context: "address:A"},
+		{name: "empty", cipher: c, ciphertext: "", context: "address:A"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.cipher.Open(tt.ciphertext, tt.context); !errors.Is(err, ErrCorrupt) {
+				t.Errorf("err = %v, want ErrCorrupt", err)
+			}
+		})
+	}
+}
+
+func TestNewRejectsBadKeys(t *testing.T) {
+	if _, err := New(make([]byte, 16)); err == nil {
+		t.Error("New accepted a 16-byte key")
+	}
+	if _, err := NewFromBase64("not base64!"); err == nil {
+		t.Error("NewFromBase64 accepted a key that is not base64")
+	}
+	if _, err := NewFromBase64(base64.StdEncoding.EncodeToString(make([]byte, KeySize))); err != nil {
+		t.Errorf("NewFromBase64: %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+package gemini
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+)
+
+// ErrCassetteMiss is returned in replay mode when a prompt was never recorded.
+var ErrCassetteMiss = errors.New("prompt not found in cassette (re-record with a real API key)")
+
+type CassetteMode int
+
+const (
+	// CassetteReplay serves only recorded responses and never touches the network.
+	CassetteReplay CassetteMode = iota
+	// CassetteRecord forwards every prompt to the wrapped generator and stores the answer.
+	CassetteRecord
+)
+
+// cassetteEntry is one line of a cassette file (JSONL).
+type cassetteEntry struct {
+	Key      string `json:"key"` // Set with WithCassetteKey, or the sha256 of the prompt
+	Prompt   string `json:"prompt"`
+	Response string `json:"response"`
+}
+
+// Cassette is a TextGenerator that records real model output to a JSONL file
+// and replays it later, so negotiation runs are reproducible offline.
+type Cassette struct {
+	mode    CassetteMode
+	path    string
+	inner   TextGenerator
+	mu      sync.Mutex
+	entries map[string]cassetteEntry
+}
+
+// OpenCassette loads the cassette at path (a missing file is an empty cassette).
+// inner is only used in record mode and may be nil for replay.
+func OpenCassette(path string, mode CassetteMode, inner TextGenerator) (*Cassette, error) {
+	if mode == CassetteRecord && inner == nil {
+		return nil, errors.New("record mode needs a generator")
+	}
+	c := &Cassette{
+		mode:    mode,
+		path:    path,
+		inner:   inner,
+		entries: make(map[string]cassetteEntry),
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e cassetteEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		c.entries[e.Key] = e
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+type cassetteKeyContext struct{}
+
+// WithCassetteKey names the recording for the prompts generated under ctx, e.g.
+// "v1/lowball/1". Named entries survive template edits, which would change a
+// prompt hash; the stored prompt still shows what was recorded.
+func WithCassetteKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, cassetteKeyContext{}, key)
+}
+
+func cassetteKey(ctx context.Context, prompt string) string {
+	if key, ok := ctx.Value(cassetteKeyContext{}).(string); ok && key != "" {
+		return key
+	}
+	sum := sha256.Sum256([]byte(prompt))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *Cassette) GenerateText(ctx context.Context, prompt string) (string, error) {
+	key := cassetteKey(ctx, prompt)
+
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	c.mu.Unlock()
+
+	if c.mode == CassetteReplay {
+		if !ok {
+			return "", ErrCassetteMiss
+		}
+		return e.Response, nil
+	}
+
+	// Record: always ask the real model so the cassette reflects current behaviour
+	txt, err := c.inner.GenerateText(ctx, prompt)
+	if err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	c.entries[key] = cassetteEntry{Key: key, Prompt: prompt, Response: txt}
+	c.mu.Unlock()
+	return txt, nil
+}
+
+// Save writes all entries back to the cassette file, sorted by key for stable diffs.
+// It is a no-op in replay mode.
+func (c *Cassette) Save() error {
+	if c.mode != CassetteRecord {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keys := make([]string, 0, len(c.entries))
+	for k := range c.entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	f, err := os.Create(c.path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, k := range keys {
+		if err := enc.Encode(c.entries[k]); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,246 @@
+package gemini
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"hash/fnv"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+//go:embed prompts/*.tmpl
+var promptFS embed.FS
+
+const (
+	promptPrefix = "negotiation_"
+	promptSuffix = ".tmpl"
+	answerPrompt = "prompts/answer.tmpl"
+
+	// DefaultPromptVersion is used when no item override or experiment applies.
+	DefaultPromptVersion = "v1"
+)
+
+// NegotiationPromptData is everything a negotiation prompt template can reference.
+type NegotiationPromptData struct {
+	InitialPrice           int
+	CurrentPrice           int
+	MinPrice               int
+	Views                  int
+	DaysListed             int
+	ItemDescription        string
+	Details                ItemDetails   // Zero value leaves the prompt unchanged
+	Shipping               *ShippingInfo // Nil leaves the prompt unchanged
+	FAQ                    []FAQEntry
+	PriceHistory           []PriceChange // Oldest first; empty leaves the prompt unchanged
+	History                []MessageHistory
+	CurrentMessage         string
+	RetryInstruction       string
+	PreviousDraftContent   string
+	PreviousDraftReasoning string
+}
+
+// ItemDetails is the item's classification; empty fields are left out of the prompt.
+type ItemDetails struct {
+	Category   string // Full path, e.g. "家電・スマホ・カメラ > スマートフォン"
+	Brand      string
+	Condition  string // In words
+	Attributes []ItemAttribute
+}
+
+// ShippingInfo describes how the item ships, in words.
+type ShippingInfo struct {
+	Method     string
+	Size       string // Size class: small, compact, 60 ... 160
+	ShipFrom   string // Prefecture name
+	DaysToShip string // e.g. "1-2"
+	Payer      string // Who pays
+	Cost       string // e.g. "¥750 anywhere in Japan"
+}
+
+// ItemAttribute is one category-specific attribute.
+type ItemAttribute struct {
+	Label string
+	Value string
+}
+
+// FAQEntry is a question the seller has already answered publicly.
+type FAQEntry struct {
+	Question string
+	Answer   string
+}
+
+// PriceChange is a past change of the listing price.
+type PriceChange struct {
+	Date     string // YYYY-MM-DD
+	OldPrice int
+	NewPrice int
+	Source   string // Why it changed, in words
+}
+
+// AnswerPromptData is rendered into the public Q&A answer prompt.
+type AnswerPromptData struct {
+	ItemDescription string
+	Shipping        *ShippingInfo // Nil leaves the prompt unchanged
+	FAQ             []FAQEntry
+	Question        string
+}
+
+// ExperimentArm is one variant of a prompt A/B experiment.
+type ExperimentArm struct {
+	Version string
+	Weight  int
+}
+
+// PromptRegistry holds the versioned negotiation prompt templates embedded in
+// the binary (prompts/negotiation_<version>.tmpl) and decides which one to use.
+type PromptRegistry struct {
+	templates      map[string]*template.Template
+	answer         *template.Template
+	defaultVersion string
+	experiment     []ExperimentArm
+}
+
+func NewPromptRegistry() (*PromptRegistry, error) {
+	entries, err := promptFS.ReadDir("prompts")
+	if err != nil {
+		return nil, err
+	}
+
+	r := &PromptRegistry{
+		templates:      make(map[string]*template.Template),
+		defaultVersion: DefaultPromptVersion,
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasPrefix(name, promptPrefix) || !strings.HasSuffix(name, promptSuffix) {
+			continue
+		}
+		version := strings.TrimSuffix(strings.TrimPrefix(name, promptPrefix), promptSuffix)
+		tmpl, err := template.ParseFS(promptFS, path.Join("prompts", name))
+		if err != nil {
+			return nil, fmt.Errorf("prompt %s: %w", version, err)
+		}
+		r.templates[version] = tmpl
+	}
+
+	r.answer, err = template.ParseFS(promptFS, answerPrompt)
+	if err != nil {
+		return nil, fmt.Errorf("answer prompt: %w", err)
+	}
+
+	if _, ok := r.templates[r.defaultVersion]; !ok {
+		return nil, fmt.Errorf("default prompt %s not found", r.defaultVersion)
+	}
+	return r, nil
+}
+
+// Versions lists the available prompt versions in sorted order.
+func (r *PromptRegistry) Versions() []string {
+	versions := make([]string, 0, len(r.templates))
+	for v := range r.templates {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+func (r *PromptRegistry) Has(version string) bool {
+	_, ok := r.templates[version]
+	return ok
+}
+
+func (r *PromptRegistry) SetDefault(version string) error {
+	if !r.Has(version) {
+		return fmt.Errorf("unknown prompt version %q", version)
+	}
+	r.defaultVersion = version
+	return nil
+}
+
+// SetExperiment splits traffic between prompt versions by weight.
+// An empty slice turns the experiment off.
+func (r *PromptRegistry) SetExperiment(arms []ExperimentArm) error {
+	for _, arm := range arms {
+		if !r.Has(arm.Version) {
+			return fmt.Errorf("unknown prompt version %q", arm.Version)
+		}
+		if arm.Weight <= 0 {
+			return fmt.Errorf("prompt %s: weight must be positive", arm.Version)
+		}
+	}
+	r.experiment = arms
+	return nil
+}
+
+// ParseExperiment parses a spec like "v1:90,v2:10".
+func ParseExperiment(spec string) ([]ExperimentArm, error) {
+	var arms []ExperimentArm
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		version, weight, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid experiment arm %q (want version:weight)", part)
+		}
+		w, err := strconv.Atoi(weight)
+		if err != nil {
+			return nil, fmt.Errorf("invalid weight in %q: %w", part, err)
+		}
+		arms = append(arms, ExperimentArm{Version: version, Weight: w})
+	}
+	return arms, nil
+}
+
+// Select picks the prompt version for a conversation.
+// A known per-item override wins; otherwise bucketKey is hashed into the
+// experiment arms so the same conversation always gets the same version.
+func (r *PromptRegistry) Select(itemOverride string, bucketKey string) string {
+	if itemOverride != "" && r.Has(itemOverride) {
+		return itemOverride
+	}
+	if len(r.experiment) == 0 {
+		return r.defaultVersion
+	}
+
+	total := 0
+	for _, arm := range r.experiment {
+		total += arm.Weight
+	}
+	h := fnv.New32a()
+	h.Write([]byte(bucketKey))
+	bucket := int(h.Sum32() % uint32(total))
+	for _, arm := range r.experiment {
+		if bucket < arm.Weight {
+			return arm.Version
+		}
+		bucket -= arm.Weight
+	}
+	return r.defaultVersion
+}
+
+func (r *PromptRegistry) Render(version string, data NegotiationPromptData) (string, error) {
+	tmpl, ok := r.templates[version]
+	if !ok {
+		return "", fmt.Errorf("unknown prompt version %q", version)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// RenderAnswer renders the public Q&A prompt (not versioned or experimented on).
+func (r *PromptRegistry) RenderAnswer(data AnswerPromptData) (string, error) {
+	var buf bytes.Buffer
+	if err := r.answer.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,176 @@
+// Package middleware holds the HTTP middleware every route runs through.
+package middleware
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"net/http"
+	"regexp"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// Middleware wraps a handler.
+type Middleware func(http.Handler) http.Handler
+
+// Chain applies mws so the first one is outermost.
+func Chain(h http.Handler, mws ...Middleware) http.Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+// ------ Request ID ------
+
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// validRequestID accepts IDs a proxy or client may already have assigned.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestID tags each request with an ID (the caller's X-Request-ID if usable,
+// else a new ULID), echoes it in the response and stores it in the context.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = ulid.MustNew(ulid.Now(), ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)).String()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+	})
+}
+
+// GetRequestID returns the ID RequestID stored in ctx, or "".
+func GetRequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// ------ Logging ------
+
+// recorder captures the status and size of a response. It keeps Flush and
+// Unwrap so streaming handlers (SSE) and http.ResponseController still work.
+type recorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (rec *recorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *recorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += n
+	return n, err
+}
+
+func (rec *recorder) Flush() {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	http.NewResponseController(rec.ResponseWriter).Flush()
+}
+
+func (rec *recorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+// Logger writes one access log line per request.
+func Logger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &recorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		status := rec.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		log.Printf("%s %s %d %dB %s request_id=%s", r.Method, r.URL.Path, status, rec.bytes, time.Since(start).Round(time.Millisecond), GetRequestID(r.Context()))
+	})
+}
+
+// ------ Recovery ------
+
+// Recover turns a panicking handler into a 500 instead of a dropped connection.
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			p := recover()
+			if p == nil {
+				return
+			}
+			if p == http.ErrAbortHandler {
+				panic(p) // Deliberate abort: let net/http drop the connection
+			}
+			log.Printf("panic: %v request_id=%s\n%s", p, GetRequestID(r.Context()), debug.Stack())
+			// Same shape as the controllers' error replies
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"code": "internal", "message": "Internal Server Error"}`))
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ------ CORS ------
+
+const (
+	corsAllowMethods  = "GET, POST, PUT, PATCH, DELETE, OPTIONS"
+	corsAllowHeaders  = "Content-Type, Authorization, If-Match, " + RequestIDHeader
+	corsExposeHeaders = "ETag, " + RequestIDHeader
+)
+
+// CORS allows browsers on allowedOrigins ("*" for any) to call the API and
+// answers preflight requests itself, so routes only register real methods.
+func CORS(allowedOrigins []string) Middleware {
+	anyOrigin := false
+	allowed := make(map[string]bool)
+	for _, o := range allowedOrigins {
+		if o == "*" {
+			anyOrigin = true
+		}
+		allowed[strings.TrimSuffix(o, "/")] = true
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin != "" {
+				h := w.Header()
+				if anyOrigin {
+					h.Set("Access-Control-Allow-Origin", "*")
+				} else {
+					h.Add("Vary", "Origin") // The answer depends on the caller
+					if allowed[origin] {
+						h.Set("Access-Control-Allow-Origin", origin)
+					}
+				}
+				h.Set("Access-Control-Expose-Headers", corsExposeHeaders)
+			}
+
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				h := w.Header()
+				h.Set("Access-Control-Allow-Methods", corsAllowMethods)
+				h.Set("Access-Control-Allow-Headers", corsAllowHeaders)
+				h.Set("Access-Control-Max-Age", "600")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// EmailConfig points at an SMTP server. For local development any fake SMTP
+// server works (docker-compose ships MailHog on localhost:1025, UI on :8025).
+type EmailConfig struct {
+	Host     string
+	Port     string
+	From     string
+	Username string // Optional; no AUTH when empty
+	Password string
+}
+
+type EmailNotifier struct {
+	cfg EmailConfig
+}
+
+func NewEmailNotifier(cfg EmailConfig) *EmailNotifier {
+	return &EmailNotifier{cfg: cfg}
+}
+
+func (e *EmailNotifier) Channel() string {
+	return ChannelEmail
+}
+
+func (e *EmailNotifier) Send(ctx context.Context, n Notification) error {
+	if n.Email == "" {
+		return errors.New("recipient has no email address")
+	}
+
+	var auth smtp.Auth
+	if e.cfg.Username != "" {
+		auth = smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, e.cfg.Host)
+	}
+
+	msg := buildEmail(e.cfg.From, n.Email, n.Title, n.Body)
+	addr := net.JoinHostPort(e.cfg.Host, e.cfg.Port)
+
+	// net/smtp has no context support; run it aside so callers can give up on a slow server
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(addr, auth, e.cfg.From, []string{n.Email}, msg)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func buildEmail(from, to, subject, body string) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
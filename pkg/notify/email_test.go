@@ -0,0 +1,123 @@
+package notify
+
+import (
+	"context"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+// smtpMessage is what the fake server received in one session.
+type smtpMessage struct {
+	from string
+	to   []string
+	data string
+}
+
+// fakeSMTP accepts a single SMTP session on a local port and reports what it received.
+func fakeSMTP(t *testing.T) (host, port string, received <-chan smtpMessage) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	ch := make(chan smtpMessage, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+		tp := textproto.NewConn(conn)
+		var msg smtpMessage
+		tp.PrintfLine("220 fake ESMTP")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(line)
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				tp.PrintfLine("250 fake")
+			case strings.HasPrefix(cmd, "MAIL FROM:"):
+				msg.from = strings.Trim(line[len("MAIL FROM:"):], "<> ")
+				tp.PrintfLine("250 OK")
+			case strings.HasPrefix(cmd, "RCPT TO:"):
+				msg.to = append(msg.to, strings.Trim(line[len("RCPT TO:"):], "<> "))
+				tp.PrintfLine("250 OK")
+			case cmd == "DATA":
+				tp.PrintfLine("354 go ahead")
+				data, err := tp.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				msg.data = string(data)
+				tp.PrintfLine("250 queued")
+			case cmd == "QUIT":
+				tp.PrintfLine("221 bye")
+				ch <- msg
+				return
+			default:
+				tp.PrintfLine("502 not implemented")
+			}
+		}
+	}()
+
+	host, port, _ = net.SplitHostPort(ln.Addr().String())
+	return host, port, ch
+}
+
+func TestEmailNotifierSend(t *testing.T) {
+	host, port, received := fakeSMTP(t)
+	n := NewEmailNotifier(EmailConfig{Host: host, Port: port, From: "noreply@example.com"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := n.Send(ctx, Notification{
+		Email: "buyer@example.com",
+		Title: "Your item sold: カメラ",
+		Body:  "Camera was bought.\nShip it soon.",
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	var msg smtpMessage
+	select {
+	case msg = <-received:
+	case <-ctx.Done():
+		t.Fatal("the fake server received no message")
+	}
+
+	if msg.from != "noreply@example.com" {
+		t.Errorf("MAIL FROM = %q", msg.from)
+	}
+	if len(msg.to) != 1 || msg.to[0] != "buyer@example.com" {
+		t.Errorf("RCPT TO = %v", msg.to)
+	}
+	for _, want := range []string{
+		"From: noreply@example.com\n",
+		"To: buyer@example.com\n",
+		"Subject: =?UTF-8?q?Your_item_sold:_=E3=82=AB=E3=83=A1=E3=83=A9?=\n",
+		"Content-Type: text/plain; charset=UTF-8\n",
+		"\n\nCamera was bought.\nShip it soon.\n",
+	} {
+		if !strings.Contains(msg.data, want) {
+			t.Errorf("message is missing %q:\n%s", want, msg.data)
+		}
+	}
+}
+
+func TestEmailNotifierSendWithoutAddress(t *testing.T) {
+	n := NewEmailNotifier(EmailConfig{Host: "127.0.0.1", Port: "1", From: "noreply@example.com"})
+	if err := n.Send(context.Background(), Notification{Title: "t", Body: "b"}); err == nil {
+		t.Fatal("Send without an email address succeeded")
+	}
+}
@@ -0,0 +1,45 @@
+// Package notify delivers user notifications over pluggable channels.
+// It knows nothing about storage: callers resolve the recipient's address,
+// push subscriptions and preferences before calling a Notifier.
+package notify
+
+import (
+	"context"
+	"time"
+)
+
+// Delivery channels, also used as keys in user notification preferences.
+const (
+	ChannelInApp   = "in_app"
+	ChannelEmail   = "email"
+	ChannelWebPush = "web_push"
+)
+
+// Notification is one event addressed to one user, with everything a channel needs to deliver it.
+type Notification struct {
+	ID        string
+	UserID    string
+	Type      string // e.g. draft_pending, reply_approved, item_sold
+	Title     string
+	Body      string
+	ItemID    string
+	MessageID string
+	CreatedAt time.Time
+
+	// Recipient details, filled in by the caller for the channels that need them
+	Email             string
+	PushSubscriptions []PushSubscription
+}
+
+// PushSubscription is a browser Push API subscription.
+type PushSubscription struct {
+	Endpoint string
+	P256dh   string
+	Auth     string
+}
+
+type Notifier interface {
+	// Channel names the delivery channel (ChannelInApp, ChannelEmail, ...).
+	Channel() string
+	Send(ctx context.Context, n Notification) error
+}
@@ -0,0 +1,106 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	webpush "github.com/SherClockHolmes/webpush-go"
+)
+
+// ErrSubscriptionGone reports push endpoints the browser has unsubscribed;
+// callers should delete them.
+type ErrSubscriptionGone struct {
+	Endpoints []string
+}
+
+func (e *ErrSubscriptionGone) Error() string {
+	return fmt.Sprintf("%d push subscription(s) gone", len(e.Endpoints))
+}
+
+type WebPushConfig struct {
+	VAPIDPublicKey  string
+	VAPIDPrivateKey string
+	Subscriber      string // Contact URL or email sent to push services
+	TTL             int    // Seconds the push service keeps an undelivered message
+}
+
+type WebPushNotifier struct {
+	cfg WebPushConfig
+}
+
+func NewWebPushNotifier(cfg WebPushConfig) *WebPushNotifier {
+	if cfg.TTL == 0 {
+		cfg.TTL = 60 * 60 * 24
+	}
+	return &WebPushNotifier{cfg: cfg}
+}
+
+func (w *WebPushNotifier) Channel() string {
+	return ChannelWebPush
+}
+
+// pushPayload is what the service worker receives.
+type pushPayload struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Body      string `json:"body"`
+	ItemID    string `json:"item_id,omitempty"`
+	MessageID string `json:"message_id,omitempty"`
+}
+
+// Send pushes to every subscription of the user. It keeps going past failures
+// and reports gone subscriptions separately from other errors.
+func (w *WebPushNotifier) Send(ctx context.Context, n Notification) error {
+	if len(n.PushSubscriptions) == 0 {
+		return nil
+	}
+	payload, err := json.Marshal(pushPayload{
+		ID:        n.ID,
+		Type:      n.Type,
+		Title:     n.Title,
+		Body:      n.Body,
+		ItemID:    n.ItemID,
+		MessageID: n.MessageID,
+	})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	gone := &ErrSubscriptionGone{}
+	for _, sub := range n.PushSubscriptions {
+		resp, err := webpush.SendNotificationWithContext(ctx, payload, &webpush.Subscription{
+			Endpoint: sub.Endpoint,
+			Keys:     webpush.Keys{P256dh: sub.P256dh, Auth: sub.Auth},
+		}, &webpush.Options{
+			Subscriber:      w.cfg.Subscriber,
+			VAPIDPublicKey:  w.cfg.VAPIDPublicKey,
+			VAPIDPrivateKey: w.cfg.VAPIDPrivateKey,
+			TTL:             w.cfg.TTL,
+		})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		resp.Body.Close()
+		switch {
+		case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+			gone.Endpoints = append(gone.Endpoints, sub.Endpoint)
+		case resp.StatusCode >= 400:
+			errs = append(errs, fmt.Errorf("push service returned %d", resp.StatusCode))
+		}
+	}
+	if len(gone.Endpoints) > 0 {
+		errs = append(errs, gone)
+	}
+	return errors.Join(errs...)
+}
+
+// PublicKey is the VAPID application server key browsers subscribe with.
+func (w *WebPushNotifier) PublicKey() string {
+	return w.cfg.VAPIDPublicKey
+}
@@ -0,0 +1,18 @@
+// Package retry computes retry delays for the background queues (webhooks, domain events, AI jobs).
+package retry
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Backoff is the wait before retry number attempt (1-based): exponential from
+// base, capped at max, with up to 20% jitter so failing work doesn't retry in lockstep.
+func Backoff(attempt int, base, max time.Duration) time.Duration {
+	d := time.Duration(float64(base) * math.Pow(2, float64(attempt-1)))
+	if d > max || d <= 0 {
+		d = max
+	}
+	return d + time.Duration(rand.Int63n(int64(d)/5+1))
+}
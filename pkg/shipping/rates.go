@@ -0,0 +1,96 @@
+package shipping
+
+// The rate table. Prices are in yen and include tax. Flat-rate methods cost the
+// same nationwide; the standard courier adds perZone for every zone between the
+// regions of sender and destination, counted along the archipelago.
+
+type methodRates struct {
+	base    map[string]int // By size class
+	perZone int            // 0 for flat-rate methods
+}
+
+var rateTable = map[string]methodRates{
+	MethodRakuraku: {base: map[string]int{
+		SizeSmall: 210, SizeCompact: 450,
+		"60": 750, "80": 850, "100": 1050, "120": 1200, "140": 1450, "160": 1700,
+	}},
+	MethodYuyu: {base: map[string]int{
+		SizeSmall: 230, SizeCompact: 455,
+		"60": 750, "80": 870, "100": 1070, "120": 1200, "140": 1450, "160": 1700,
+	}},
+	MethodStandard: {base: map[string]int{
+		"60": 940, "80": 1210, "100": 1480, "120": 1750, "140": 2020, "160": 2290,
+	}, perZone: 110},
+}
+
+// Regions, numbered as shipping zones from north to south. Okinawa is two
+// zones past Kyushu because parcels go by air.
+const (
+	regionHokkaido = 0
+	regionTohoku   = 1
+	regionKanto    = 2
+	regionShinetsu = 2
+	regionChubu    = 3
+	regionHokuriku = 3
+	regionKansai   = 4
+	regionChugoku  = 5
+	regionShikoku  = 5
+	regionKyushu   = 6
+	regionOkinawa  = 8
+)
+
+type prefecture struct {
+	name   string
+	region int
+}
+
+// prefectures by JIS code.
+var prefectures = map[string]prefecture{
+	"01": {"北海道", regionHokkaido},
+	"02": {"青森県", regionTohoku},
+	"03": {"岩手県", regionTohoku},
+	"04": {"宮城県", regionTohoku},
+	"05": {"秋田県", regionTohoku},
+	"06": {"山形県", regionTohoku},
+	"07": {"福島県", regionTohoku},
+	"08": {"茨城県", regionKanto},
+	"09": {"栃木県", regionKanto},
+	"10": {"群馬県", regionKanto},
+	"11": {"埼玉県", regionKanto},
+	"12": {"千葉県", regionKanto},
+	"13": {"東京都", regionKanto},
+	"14": {"神奈川県", regionKanto},
+	"15": {"新潟県", regionShinetsu},
+	"16": {"富山県", regionHokuriku},
+	"17": {"石川県", regionHokuriku},
+	"18": {"福井県", regionHokuriku},
+	"19": {"山梨県", regionKanto},
+	"20": {"長野県", regionShinetsu},
+	"21": {"岐阜県", regionChubu},
+	"22": {"静岡県", regionChubu},
+	"23": {"愛知県", regionChubu},
+	"24": {"三重県", regionChubu},
+	"25": {"滋賀県", regionKansai},
+	"26": {"京都府", regionKansai},
+	"27": {"大阪府", regionKansai},
+	"28": {"兵庫県", regionKansai},
+	"29": {"奈良県", regionKansai},
+	"30": {"和歌山県", regionKansai},
+	"31": {"鳥取県", regionChugoku},
+	"32": {"島根県", regionChugoku},
+	"33": {"岡山県", regionChugoku},
+	"34": {"広島県", regionChugoku},
+	"35": {"山口県", regionChugoku},
+	"36": {"徳島県", regionShikoku},
+	"37": {"香川県", regionShikoku},
+	"38": {"愛媛県", regionShikoku},
+	"39": {"高知県", regionShikoku},
+	"40": {"福岡県", regionKyushu},
+	"41": {"佐賀県", regionKyushu},
+	"42": {"長崎県", regionKyushu},
+	"43": {"熊本県", regionKyushu},
+	"44": {"大分県", regionKyushu},
+	"45": {"宮崎県", regionKyushu},
+	"46": {"鹿児島県", regionKyushu},
+	"47": {"沖縄県", regionOkinawa},
+}
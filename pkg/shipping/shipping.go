@@ -0,0 +1,108 @@
+// Package shipping prices parcels from the local rate table (rates.go): a
+// shipping method, a size class and the ship-from and destination prefectures.
+package shipping
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Shipping methods.
+const (
+	MethodRakuraku = "rakuraku" // Anonymous courier, flat nationwide rate by size
+	MethodYuyu     = "yuyu"     // Anonymous post office parcel, flat nationwide rate by size
+	MethodStandard = "standard" // Regular courier, priced by size and distance
+)
+
+// Who pays the shipping.
+const (
+	PayerSeller = "seller" // Included in the price
+	PayerBuyer  = "buyer"  // Added to the price at purchase
+)
+
+// Size classes: "small" fits a mail slot (A4, up to 3 cm), "compact" a small
+// box, and the numbers are the sum of the three sides in cm.
+const (
+	SizeSmall   = "small"
+	SizeCompact = "compact"
+)
+
+var (
+	Methods    = []string{MethodRakuraku, MethodYuyu, MethodStandard}
+	Payers     = []string{PayerSeller, PayerBuyer}
+	Sizes      = []string{SizeSmall, SizeCompact, "60", "80", "100", "120", "140", "160"}
+	DaysToShip = []string{"1-2", "2-3", "4-7"}
+)
+
+var (
+	ErrUnknownMethod     = errors.New("unknown shipping method")
+	ErrUnsupportedSize   = errors.New("size not available for this shipping method")
+	ErrUnknownPrefecture = errors.New("unknown prefecture")
+)
+
+// Cost is the price of sending a parcel of the size class from one prefecture
+// to another, both JIS codes ("01" Hokkaido to "47" Okinawa).
+func Cost(method string, size string, from string, to string) (int, error) {
+	rates, ok := rateTable[method]
+	if !ok {
+		return 0, ErrUnknownMethod
+	}
+	base, ok := rates.base[size]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s by %s", ErrUnsupportedSize, size, method)
+	}
+	src, ok := prefectures[from]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownPrefecture, from)
+	}
+	dst, ok := prefectures[to]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownPrefecture, to)
+	}
+	if rates.perZone == 0 {
+		return base, nil
+	}
+	return base + rates.perZone*zoneDistance(src.region, dst.region), nil
+}
+
+// CostRange is the cheapest and dearest destination for a parcel, for when the
+// destination isn't known yet. Flat-rate methods return the same price twice.
+func CostRange(method string, size string, from string) (min int, max int, err error) {
+	for code := range prefectures {
+		cost, err := Cost(method, size, from, code)
+		if err != nil {
+			return 0, 0, err
+		}
+		if min == 0 || cost < min {
+			min = cost
+		}
+		if cost > max {
+			max = cost
+		}
+	}
+	return min, max, nil
+}
+
+// Supports reports whether the method takes parcels of the size class.
+func Supports(method string, size string) bool {
+	_, ok := rateTable[method].base[size]
+	return ok
+}
+
+// PrefectureName returns the prefecture's name, e.g. "東京都" for "13", or "".
+func PrefectureName(code string) string {
+	return prefectures[code].name
+}
+
+// IsPrefecture reports whether code is a JIS prefecture code.
+func IsPrefecture(code string) bool {
+	_, ok := prefectures[code]
+	return ok
+}
+
+func zoneDistance(a, b int) int {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
@@ -0,0 +1,80 @@
+package shipping
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCost(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		size     string
+		from, to string
+		want     int
+		err      error
+	}{
+		{name: "flat rate, same prefecture", method: MethodRakuraku, size: "60", from: "13", to: "13", want: 750},
+		{name: "flat rate, across the country", method: MethodRakuraku, size: "60", from: "01", to: "47", want: 750},
+		{name: "flat rate small", method: MethodYuyu, size: SizeSmall, from: "27", to: "01", want: 230},
+		{name: "zoned, same region", method: MethodStandard, size: "60", from: "13", to: "14", want: 940},
+		{name: "zoned, Tokyo to Hokkaido", method: MethodStandard, size: "60", from: "13", to: "01", want: 940 + 2*110},
+		{name: "zoned, Tokyo to Okinawa", method: MethodStandard, size: "100", from: "13", to: "47", want: 1480 + 6*110},
+		{name: "zoned is symmetric", method: MethodStandard, size: "100", from: "47", to: "13", want: 1480 + 6*110},
+		{name: "unknown method", method: "drone", size: "60", from: "13", to: "13", err: ErrUnknownMethod},
+		{name: "standard takes no small parcels", method: MethodStandard, size: SizeSmall, from: "13", to: "13", err: ErrUnsupportedSize},
+		{name: "unknown size", method: MethodRakuraku, size: "200", from: "13", to: "13", err: ErrUnsupportedSize},
+		{name: "unknown origin", method: MethodRakuraku, size: "60", from: "48", to: "13", err: ErrUnknownPrefecture},
+		{name: "unknown destination", method: MethodRakuraku, size: "60", from: "13", to: "", err: ErrUnknownPrefecture},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Cost(tt.method, tt.size, tt.from, tt.to)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("cost = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCostRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		size     string
+		from     string
+		min, max int
+		err      error
+	}{
+		{name: "flat rate", method: MethodRakuraku, size: SizeCompact, from: "13", min: 450, max: 450},
+		{name: "zoned from Tokyo", method: MethodStandard, size: "60", from: "13", min: 940, max: 940 + 6*110},
+		{name: "zoned from Okinawa", method: MethodStandard, size: "60", from: "47", min: 940, max: 940 + 8*110},
+		{name: "unsupported size", method: MethodStandard, size: SizeCompact, from: "13", err: ErrUnsupportedSize},
+		{name: "unknown origin", method: MethodYuyu, size: "60", from: "00", err: ErrUnknownPrefecture},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			min, max, err := CostRange(tt.method, tt.size, tt.from)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if min != tt.min || max != tt.max {
+				t.Errorf("range = %d-%d, want %d-%d", min, max, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestSupports(t *testing.T) {
+	for _, method := range Methods {
+		for _, size := range Sizes {
+			_, err := Cost(method, size, "13", "13")
+			if got := Supports(method, size); got != (err == nil) {
+				t.Errorf("Supports(%s, %s) = %v, but Cost returned %v", method, size, got, err)
+			}
+		}
+	}
+}
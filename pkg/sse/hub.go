@@ -0,0 +1,130 @@
+// Package sse fans out server-sent events to connected users.
+package sse
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// clientBuffer is how many events a slow client may lag behind before events are dropped for it.
+const clientBuffer = 16
+
+type Event struct {
+	ID   string
+	Type string
+	Data []byte // JSON, single line
+}
+
+// Hub keeps the open streams of each user.
+type Hub struct {
+	mu      sync.RWMutex
+	clients map[string]map[chan Event]struct{}
+	closed  bool
+}
+
+func NewHub() *Hub {
+	return &Hub{clients: make(map[string]map[chan Event]struct{})}
+}
+
+// Subscribe opens a stream for userID. Call the returned func to close it.
+func (h *Hub) Subscribe(userID string) (<-chan Event, func()) {
+	ch := make(chan Event, clientBuffer)
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		close(ch)
+		return ch, func() {}
+	}
+	if h.clients[userID] == nil {
+		h.clients[userID] = make(map[chan Event]struct{})
+	}
+	h.clients[userID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.clients[userID], ch)
+		if len(h.clients[userID]) == 0 {
+			delete(h.clients, userID)
+		}
+		h.mu.Unlock()
+	}
+}
+
+// Publish sends an event to every open stream of userID without blocking.
+// Streams are best effort: a client that reconnects refetches state over REST.
+func (h *Hub) Publish(userID string, e Event) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for ch := range h.clients[userID] {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// Close ends every open stream (their channels are closed) and refuses new ones.
+// Used on shutdown, since streams would otherwise keep the server from draining.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	h.closed = true
+	for _, chans := range h.clients {
+		for ch := range chans {
+			close(ch)
+		}
+	}
+	h.clients = make(map[string]map[chan Event]struct{})
+}
+
+// Serve streams events to the client until it disconnects or the channel is
+// closed, with a comment heartbeat so proxies keep the connection open.
+func Serve(w http.ResponseWriter, r *http.Request, events <-chan Event, heartbeat time.Duration) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	// A stream outlives the server's WriteTimeout; each write is kept alive by the heartbeat instead
+	http.NewResponseController(w).SetWriteDeadline(time.Time{})
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			if e.ID != "" {
+				fmt.Fprintf(w, "id: %s\n", e.ID)
+			}
+			if e.Type != "" {
+				fmt.Fprintf(w, "event: %s\n", e.Type)
+			}
+			for _, line := range strings.Split(string(e.Data), "\n") {
+				fmt.Fprintf(w, "data: %s\n", line)
+			}
+			fmt.Fprint(w, "\n")
+		}
+		flusher.Flush()
+	}
+}
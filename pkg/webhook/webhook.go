@@ -0,0 +1,197 @@
+// Package webhook signs and sends webhook deliveries.
+//
+// Every request carries the headers
+//
+//	X-Webhook-Id:        event ID (stable across retries; use it to deduplicate)
+//	X-Webhook-Event:     event type, e.g. item.sold
+//	X-Webhook-Signature: t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>" keyed with the endpoint secret>
+//
+// Receivers should recompute the HMAC and reject stale timestamps.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	HeaderID        = "X-Webhook-Id"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// NewSecret returns a random signing secret for a new endpoint.
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// Sign returns the X-Webhook-Signature value for body at time t.
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature header against body, rejecting timestamps older than tolerance.
+func Verify(secret string, header string, body []byte, tolerance time.Duration) bool {
+	var ts, sig string
+	for _, part := range strings.Split(header, ",") {
+		k, v, _ := strings.Cut(part, "=")
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			sig = v
+		}
+	}
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || sig == "" {
+		return false
+	}
+	t := time.Unix(sec, 0)
+	if tolerance > 0 && time.Since(t) > tolerance {
+		return false
+	}
+	expected := Sign(secret, t, body)
+	return hmac.Equal([]byte(expected), []byte("t="+ts+",v1="+sig))
+}
+
+// Result describes one delivery attempt.
+type Result struct {
+	StatusCode int
+	Duration   time.Duration
+	Err        error
+}
+
+// OK reports a 2xx response.
+func (r Result) OK() bool {
+	return r.Err == nil && r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// ErrPrivateAddress rejects endpoints that resolve to loopback, private,
+// link-local or other non-public addresses, so webhooks can't probe the
+// server's own network.
+var ErrPrivateAddress = errors.New("webhook host resolves to a non-public address")
+
+// Ranges that are neither covered by the net.IP predicates nor publicly routable.
+var reservedNets = []*net.IPNet{
+	mustCIDR("0.0.0.0/8"),
+	mustCIDR("100.64.0.0/10"), // Carrier-grade NAT
+	mustCIDR("192.0.0.0/24"),
+	mustCIDR("198.18.0.0/15"), // Benchmarking
+	mustCIDR("240.0.0.0/4"),
+}
+
+func mustCIDR(s string) *net.IPNet {
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// IsPublicIP reports whether ip is a globally routable unicast address.
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, n := range reservedNets {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckHost resolves host and fails with ErrPrivateAddress if any of its
+// addresses is not public. Sender checks again at connect time, which also
+// covers DNS answers that change after registration.
+func CheckHost(ctx context.Context, host string) error {
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return err
+	}
+	for _, a := range addrs {
+		if !IsPublicIP(a.IP) {
+			return ErrPrivateAddress
+		}
+	}
+	return nil
+}
+
+// dialPublicOnly refuses connections to non-public addresses. It runs after
+// name resolution, on the address actually dialled.
+func dialPublicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !IsPublicIP(ip) {
+		return ErrPrivateAddress
+	}
+	return nil
+}
+
+type Sender struct {
+	client *http.Client
+}
+
+func NewSender(timeout time.Duration) *Sender {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil // A proxy would be the address checked, not the endpoint
+	transport.DialContext = (&net.Dialer{Timeout: timeout, Control: dialPublicOnly}).DialContext
+	return &Sender{client: &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		// Don't follow redirects: a delivery goes to the registered URL only
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}}
+}
+
+// Send POSTs a signed JSON payload.
+func (s *Sender) Send(ctx context.Context, url, secret, eventID, eventType string, payload []byte) Result {
+	start := time.Now()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return Result{Err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "hackathon-backend-webhooks/1.0")
+	req.Header.Set(HeaderID, eventID)
+	req.Header.Set(HeaderEvent, eventType)
+	req.Header.Set(HeaderSignature, Sign(secret, start, payload))
+
+	resp, err := s.client.Do(req)
+	res := Result{Duration: time.Since(start), Err: err}
+	if err != nil {
+		return res
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	res.StatusCode = resp.StatusCode
+	if !res.OK() {
+		res.Err = fmt.Errorf("endpoint returned %d", resp.StatusCode)
+	}
+	return res
+}
@@ -0,0 +1,77 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip     string
+		public bool
+	}{
+		{"8.8.8.8", true},
+		{"2001:4860:4860::8888", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.0.0.5", false},
+		{"172.16.3.4", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false}, // Cloud metadata
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"100.64.0.1", false},
+		{"224.0.0.1", false},
+		{"::ffff:127.0.0.1", false},
+	}
+	for _, tt := range tests {
+		if got := IsPublicIP(net.ParseIP(tt.ip)); got != tt.public {
+			t.Errorf("IsPublicIP(%s) = %v, want %v", tt.ip, got, tt.public)
+		}
+	}
+}
+
+func TestCheckHostRejectsLoopback(t *testing.T) {
+	for _, host := range []string{"127.0.0.1", "localhost", "169.254.169.254"} {
+		if err := CheckHost(context.Background(), host); !errors.Is(err, ErrPrivateAddress) {
+			t.Errorf("CheckHost(%s) = %v, want ErrPrivateAddress", host, err)
+		}
+	}
+}
+
+func TestSenderRefusesPrivateAddress(t *testing.T) {
+	hit := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+	}))
+	defer srv.Close()
+
+	res := NewSender(5*time.Second).Send(context.Background(), srv.URL, "whsec_test", "evt", "item.sold", []byte(`{}`))
+	if !errors.Is(res.Err, ErrPrivateAddress) {
+		t.Fatalf("Send to %s: err = %v, want ErrPrivateAddress", srv.URL, res.Err)
+	}
+	if hit {
+		t.Fatal("the request reached the server")
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+	header := Sign("secret", time.Now(), body)
+	if !Verify("secret", header, body, time.Minute) {
+		t.Fatal("signature did not verify")
+	}
+	if Verify("other", header, body, time.Minute) {
+		t.Fatal("signature verified with the wrong secret")
+	}
+	if Verify("secret", Sign("secret", time.Now().Add(-time.Hour), body), body, time.Minute) {
+		t.Fatal("stale signature verified")
+	}
+}
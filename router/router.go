@@ -0,0 +1,121 @@
+// Package router maps every HTTP route to its controller handler and wraps
+// them in the middleware chain.
+package router
+
+import (
+	"hackathon-backend/controller"
+	"hackathon-backend/pkg/middleware"
+	"net/http"
+)
+
+type Controllers struct {
+	Item         *controller.ItemController
+	User         *controller.UserController
+	Negotiation  *controller.NegotiationController
+	Notification *controller.NotificationController
+	Webhook      *controller.WebhookController
+	Stream       *controller.StreamController
+	Analytics    *controller.AnalyticsController
+	Watch        *controller.WatchController
+	Category     *controller.CategoryController
+	Address      *controller.AddressController
+	OrderCase    *controller.OrderCaseController
+}
+
+type Config struct {
+	AllowedOrigins []string // CORS; "*" allows any
+}
+
+// New returns the API handler. Unknown paths get 404 and known paths with
+// another method get 405 with an Allow header, both from http.ServeMux.
+func New(c Controllers, cfg Config) http.Handler {
+	mux := http.NewServeMux()
+
+	// Items
+	mux.HandleFunc("GET /items", c.Item.ListItems)
+	mux.HandleFunc("POST /items", c.Item.CreateItem)
+	mux.HandleFunc("GET /items/{id}", c.Item.GetItem)
+	mux.HandleFunc("PUT /items/{id}", c.Item.UpdateItem)
+	mux.HandleFunc("PATCH /items/{id}", c.Item.PatchItem)
+	mux.HandleFunc("DELETE /items/{id}", c.Item.DeleteItem)
+	mux.HandleFunc("PUT /items/{id}/buy", c.Item.PurchaseItem)
+	mux.HandleFunc("GET /items/{id}/faq", c.Item.GetFAQ)
+	mux.HandleFunc("GET /items/{id}/price-history", c.Item.GetPriceHistory)
+	mux.HandleFunc("GET /items/{id}/shipping-quote", c.Item.GetShippingQuote)
+	mux.HandleFunc("GET /items/{id}/shipping-address", c.Address.GetPurchaseAddress)
+	mux.HandleFunc("GET /items/{id}/threads", c.Item.GetThreads)
+
+	// Cancellations and disputes on sold items
+	mux.HandleFunc("GET /items/{id}/cases", c.OrderCase.ListItemCases)
+	mux.HandleFunc("POST /items/{id}/cancellation", c.OrderCase.RequestCancellation)
+	mux.HandleFunc("POST /items/{id}/disputes", c.OrderCase.OpenDispute)
+	mux.HandleFunc("GET /cases/{id}", c.OrderCase.GetCase)
+	mux.HandleFunc("POST /cases/{id}/messages", c.OrderCase.AddMessage)
+	mux.HandleFunc("PUT /cases/{id}/accept", c.OrderCase.Accept)
+	mux.HandleFunc("PUT /cases/{id}/decline", c.OrderCase.Decline)
+	mux.HandleFunc("PUT /cases/{id}/withdraw", c.OrderCase.Withdraw)
+	mux.HandleFunc("PUT /cases/{id}/resolve", c.OrderCase.Resolve)
+	mux.HandleFunc("GET /admin/cases", c.OrderCase.ListCases)
+
+	// Shipping rates
+	mux.HandleFunc("GET /shipping/estimate", c.Item.EstimateShipping)
+
+	// Categories
+	mux.HandleFunc("GET /categories", c.Category.ListCategories)
+	mux.HandleFunc("GET /categories/{id}", c.Category.GetCategory)
+
+	// Watches and likes (price-drop alerts)
+	mux.HandleFunc("PUT /items/{id}/watch", c.Watch.Watch)
+	mux.HandleFunc("DELETE /items/{id}/watch", c.Watch.Unwatch)
+	mux.HandleFunc("PUT /items/{id}/like", c.Watch.Like)
+	mux.HandleFunc("DELETE /items/{id}/like", c.Watch.Unlike)
+	mux.HandleFunc("GET /watches", c.Watch.ListWatches)
+
+	// Messages
+	mux.HandleFunc("GET /items/{id}/messages", c.Item.GetMessages)
+	mux.HandleFunc("POST /items/{id}/messages", c.Item.SendMessage)
+	mux.HandleFunc("PUT /items/{id}/messages/read", c.Item.MarkThreadRead)
+	mux.HandleFunc("POST /items/{id}/messages/retry", c.Item.RegenerateAIMessage)
+	mux.HandleFunc("GET /messages/{id}/job", c.Item.GetMessageJob)
+	mux.HandleFunc("PUT /messages/{id}/approve", c.Item.ApproveMessage)
+	mux.HandleFunc("PUT /messages/{id}/reject", c.Item.RejectMessage)
+	mux.HandleFunc("PUT /messages/{id}/revoke", c.Item.RevokeMessage)
+	mux.HandleFunc("GET /threads", c.Item.GetInbox)
+
+	// Users and negotiation stats
+	mux.HandleFunc("POST /register", c.User.Register)
+	mux.HandleFunc("GET /addresses", c.Address.ListAddresses)
+	mux.HandleFunc("POST /addresses", c.Address.CreateAddress)
+	mux.HandleFunc("PUT /addresses/{id}", c.Address.UpdateAddress)
+	mux.HandleFunc("DELETE /addresses/{id}", c.Address.DeleteAddress)
+	mux.HandleFunc("GET /negotiations/prompt-report", c.Negotiation.GetPromptReport)
+	mux.HandleFunc("GET /sellers/{id}/negotiations/stats", c.Negotiation.GetSellerStats)
+
+	// Notifications
+	mux.HandleFunc("GET /notifications", c.Notification.ListNotifications)
+	mux.HandleFunc("PUT /notifications/{id}/read", c.Notification.MarkRead)
+	mux.HandleFunc("PUT /notifications/read-all", c.Notification.MarkAllRead)
+	mux.HandleFunc("GET /notification-preferences", c.Notification.GetPreferences)
+	mux.HandleFunc("PUT /notification-preferences", c.Notification.UpdatePreferences)
+	mux.HandleFunc("GET /push-subscriptions", c.Notification.GetVAPIDPublicKey)
+	mux.HandleFunc("POST /push-subscriptions", c.Notification.Subscribe)
+	mux.HandleFunc("DELETE /push-subscriptions", c.Notification.Unsubscribe)
+
+	// Webhooks
+	mux.HandleFunc("GET /webhooks", c.Webhook.ListWebhooks)
+	mux.HandleFunc("POST /webhooks", c.Webhook.RegisterWebhook)
+	mux.HandleFunc("DELETE /webhooks/{id}", c.Webhook.DeleteWebhook)
+	mux.HandleFunc("GET /webhooks/{id}/deliveries", c.Webhook.ListDeliveries)
+	mux.HandleFunc("POST /webhooks/{id}/deliveries/{delivery_id}/redeliver", c.Webhook.Redeliver)
+
+	// Live events and analytics
+	mux.HandleFunc("GET /events/stream", c.Stream.Stream)
+	mux.HandleFunc("GET /analytics/events", c.Analytics.GetEventCounts)
+
+	return middleware.Chain(mux,
+		middleware.RequestID,
+		middleware.Logger,
+		middleware.Recover,
+		middleware.CORS(cfg.AllowedOrigins), // Inside Recover so a 500 still carries CORS headers
+	)
+}
@@ -1,25 +1,21 @@
 package main
 
 import (
-	"database/sql"
 	"fmt"
+	"hackathon-backend/config"
+	"hackathon-backend/dao"
 	"log"
-	"os"
-
-	_ "github.com/go-sql-driver/mysql"
 )
 
 func main() {
-    // Load Env (Minimal approach, assuming env vars are set in shell)
-    user := os.Getenv("MYSQL_USER")
-    pwd := os.Getenv("MYSQL_PWD")
-    host := os.Getenv("MYSQL_HOST")
-    dbName := os.Getenv("MYSQL_DATABASE")
-
-    dsn := fmt.Sprintf("%s:%s@tcp(%s)/%s?parseTime=true", user, pwd, host, dbName)
-    db, err := sql.Open("mysql", dsn)
+    // Same settings as the server (env / CONFIG_FILE)
+    cfg, err := config.Load()
     if err != nil {
-        log.Fatal(err)
+        log.Fatal("Invalid configuration:\n", err)
+    }
+    db, err := dao.OpenDB(cfg.DB)
+    if err != nil {
+        log.Fatal("Failed to connect to DB:", err)
     }
     defer db.Close()
 
@@ -0,0 +1,348 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hackathon-backend/dao"
+	"hackathon-backend/model"
+	"hackathon-backend/pkg/fieldcrypt"
+	"hackathon-backend/pkg/shipping"
+	"math/rand"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const maxAddressesPerUser = 10
+
+var (
+	postalCodePattern = regexp.MustCompile(`^(\d{3})-?(\d{4})$`)
+	phonePattern      = regexp.MustCompile(`^0\d{9,10}$`) // Domestic, hyphens removed
+)
+
+// sealedAddressFields is the encrypted part of an address.
+type sealedAddressFields struct {
+	Label      string `json:"label,omitempty"`
+	Name       string `json:"name"`
+	PostalCode string `json:"postal_code"`
+	City       string `json:"city"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2,omitempty"`
+	Phone      string `json:"phone"`
+}
+
+// AddressUsecase keeps each user's address book, encrypted at rest, and hands
+// the address chosen at purchase to that item's seller. With no cipher (no
+// ADDRESS_ENCRYPTION_KEY) the address book is off.
+type AddressUsecase struct {
+	repo     *dao.AddressRepository
+	itemRepo *dao.ItemRepository
+	txm      *dao.TxManager
+	cipher   *fieldcrypt.Cipher
+}
+
+func NewAddressUsecase(repo *dao.AddressRepository, itemRepo *dao.ItemRepository, txm *dao.TxManager, cipher *fieldcrypt.Cipher) *AddressUsecase {
+	return &AddressUsecase{repo: repo, itemRepo: itemRepo, txm: txm, cipher: cipher}
+}
+
+// Enabled reports whether the address book is configured.
+func (u *AddressUsecase) Enabled() bool {
+	return u != nil && u.cipher != nil
+}
+
+func (u *AddressUsecase) available(userID string) error {
+	if !u.Enabled() {
+		return &Error{Kind: ErrUnavailable, Message: "the address book is not configured"}
+	}
+	if userID == "" {
+		return unauthorized()
+	}
+	return nil
+}
+
+func (u *AddressUsecase) GetAddresses(ctx context.Context, userID string) ([]model.Address, error) {
+	if err := u.available(userID); err != nil {
+		return nil, err
+	}
+	ctx, cancel := withDeadline(ctx, deadlines.Read)
+	defer cancel()
+	sealed, err := u.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	addresses := make([]model.Address, 0, len(sealed))
+	for i := range sealed {
+		a, err := u.open(&sealed[i], "address:"+sealed[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, *a)
+	}
+	return addresses, nil
+}
+
+// CreateAddress adds an address. The user's first address becomes the default.
+// The user's row is locked while the address book is counted and written, so
+// concurrent requests can't pass the limit or leave two defaults.
+func (u *AddressUsecase) CreateAddress(ctx context.Context, userID string, a model.Address) (*model.Address, error) {
+	if err := u.available(userID); err != nil {
+		return nil, err
+	}
+	if err := validateAddress(&a); err != nil {
+		return nil, err
+	}
+	a.ID = ulid.MustNew(ulid.Now(), ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)).String()
+	a.UserID = userID
+	a.CreatedAt = time.Now()
+
+	ctx, cancel := withDeadline(ctx, deadlines.Write)
+	defer cancel()
+	err := u.txm.WithTx(ctx, func(tx *dao.Tx) error {
+		if err := tx.Addresses.LockUser(ctx, userID); err != nil {
+			return err
+		}
+		existing, err := tx.Addresses.GetByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if len(existing) >= maxAddressesPerUser {
+			return conflict(fmt.Sprintf("an address book holds at most %d addresses", maxAddressesPerUser))
+		}
+
+		a.IsDefault = a.IsDefault || len(existing) == 0
+		sealed, err := u.seal(&a, "address:"+a.ID)
+		if err != nil {
+			return err
+		}
+		sealed.IsDefault = false // Set below, so there is only ever one default
+		if err := tx.Addresses.Insert(ctx, sealed); err != nil {
+			return err
+		}
+		if a.IsDefault {
+			return tx.Addresses.SetDefault(ctx, userID, a.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// UpdateAddress replaces an address. is_default can only be turned on; the
+// default moves when another address is made the default.
+func (u *AddressUsecase) UpdateAddress(ctx context.Context, id string, userID string, a model.Address) (*model.Address, error) {
+	if err := u.available(userID); err != nil {
+		return nil, err
+	}
+	if err := validateAddress(&a); err != nil {
+		return nil, err
+	}
+	ctx, cancel := withDeadline(ctx, deadlines.Write)
+	defer cancel()
+	current, err := u.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, notFound("address")
+	}
+
+	a.ID, a.UserID, a.CreatedAt = id, userID, current.CreatedAt
+	sealed, err := u.seal(&a, "address:"+id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.repo.Update(ctx, sealed); err != nil {
+		return nil, err
+	}
+	if a.IsDefault && !current.IsDefault {
+		if err := u.repo.SetDefault(ctx, userID, id); err != nil {
+			return nil, err
+		}
+	}
+	a.IsDefault = a.IsDefault || current.IsDefault
+	return &a, nil
+}
+
+// DeleteAddress removes an address. Purchases keep their own copy.
+func (u *AddressUsecase) DeleteAddress(ctx context.Context, id string, userID string) error {
+	if err := u.available(userID); err != nil {
+		return err
+	}
+	ctx, cancel := withDeadline(ctx, deadlines.Write)
+	defer cancel()
+	deleted, err := u.repo.Delete(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return notFound("address")
+	}
+	return nil
+}
+
+// GetPurchaseAddress is where a sold item ships to. Only its seller and buyer may see it.
+func (u *AddressUsecase) GetPurchaseAddress(ctx context.Context, itemID string, userID string) (*model.Address, error) {
+	if err := u.available(userID); err != nil {
+		return nil, err
+	}
+	ctx, cancel := withDeadline(ctx, deadlines.Read)
+	defer cancel()
+	item, err := u.itemRepo.GetByID(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil || item.Status == "deleted" {
+		return nil, notFound("item")
+	}
+	if item.Status != "sold" || item.BuyerID == nil {
+		return nil, conflict("the item has not been purchased")
+	}
+	if userID != item.UserID && userID != *item.BuyerID {
+		return nil, forbidden("only the seller and the buyer can see the shipping address")
+	}
+	sealed, err := u.repo.GetPurchase(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if sealed == nil {
+		return nil, notFound("shipping address")
+	}
+	return u.open(sealed, "purchase:"+itemID)
+}
+
+// purchaseAddress picks the address a purchase ships to: addressID, or the
+// buyer's default when it is empty. It returns nil if there is none to pick or
+// the address book is off.
+func (u *AddressUsecase) purchaseAddress(ctx context.Context, buyerID string, addressID string) (*model.Address, error) {
+	if !u.Enabled() {
+		if addressID != "" {
+			return nil, u.available(buyerID)
+		}
+		return nil, nil
+	}
+	if addressID != "" {
+		sealed, err := u.repo.GetByID(ctx, buyerID, addressID)
+		if err != nil {
+			return nil, err
+		}
+		if sealed == nil {
+			return nil, notFound("address")
+		}
+		return u.open(sealed, "address:"+sealed.ID)
+	}
+	sealed, err := u.repo.GetByUserID(ctx, buyerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) == 0 || !sealed[0].IsDefault {
+		return nil, nil
+	}
+	return u.open(&sealed[0], "address:"+sealed[0].ID)
+}
+
+// sealPurchase encrypts a copy of the address for the item's purchase record.
+func (u *AddressUsecase) sealPurchase(a *model.Address, itemID string) (*model.SealedAddress, error) {
+	sealed, err := u.seal(a, "purchase:"+itemID)
+	if err != nil {
+		return nil, err
+	}
+	sealed.CreatedAt = time.Now()
+	return sealed, nil
+}
+
+// seal encrypts the address fields, bound to context so a row's ciphertext
+// can't be moved to another row.
+func (u *AddressUsecase) seal(a *model.Address, context string) (*model.SealedAddress, error) {
+	plain, err := json.Marshal(sealedAddressFields{
+		Label:      a.Label,
+		Name:       a.Name,
+		PostalCode: a.PostalCode,
+		City:       a.City,
+		Line1:      a.Line1,
+		Line2:      a.Line2,
+		Phone:      a.Phone,
+	})
+	if err != nil {
+		return nil, err
+	}
+	fields, err := u.cipher.Seal(plain, context)
+	if err != nil {
+		return nil, err
+	}
+	return &model.SealedAddress{
+		ID:           a.ID,
+		UserID:       a.UserID,
+		Prefecture:   a.Prefecture,
+		SealedFields: fields,
+		IsDefault:    a.IsDefault,
+		CreatedAt:    a.CreatedAt,
+	}, nil
+}
+
+func (u *AddressUsecase) open(s *model.SealedAddress, context string) (*model.Address, error) {
+	plain, err := u.cipher.Open(s.SealedFields, context)
+	if err != nil {
+		return nil, fmt.Errorf("address %s: %w", s.ID, err)
+	}
+	var f sealedAddressFields
+	if err := json.Unmarshal(plain, &f); err != nil {
+		return nil, err
+	}
+	return &model.Address{
+		ID:         s.ID,
+		UserID:     s.UserID,
+		Label:      f.Label,
+		Name:       f.Name,
+		PostalCode: f.PostalCode,
+		Prefecture: s.Prefecture,
+		City:       f.City,
+		Line1:      f.Line1,
+		Line2:      f.Line2,
+		Phone:      f.Phone,
+		IsDefault:  s.IsDefault,
+		CreatedAt:  s.CreatedAt,
+	}, nil
+}
+
+// validateAddress trims and normalizes the address and reports every bad field.
+func validateAddress(a *model.Address) error {
+	var fields []FieldError
+	text := func(field string, value *string, max int, required bool) {
+		*value = strings.TrimSpace(*value)
+		switch {
+		case *value == "" && required:
+			fields = append(fields, FieldError{Field: field, Message: field + " is required"})
+		case utf8.RuneCountInString(*value) > max:
+			fields = append(fields, FieldError{Field: field, Message: fmt.Sprintf("%s must be at most %d characters", field, max)})
+		}
+	}
+	text("label", &a.Label, 20, false)
+	text("name", &a.Name, 50, true)
+	text("city", &a.City, 50, true)
+	text("line1", &a.Line1, 100, true)
+	text("line2", &a.Line2, 100, false)
+
+	if m := postalCodePattern.FindStringSubmatch(strings.TrimSpace(a.PostalCode)); m != nil {
+		a.PostalCode = m[1] + "-" + m[2]
+	} else {
+		fields = append(fields, FieldError{Field: "postal_code", Message: "postal_code must be 7 digits, e.g. 150-0001"})
+	}
+	if !shipping.IsPrefecture(a.Prefecture) {
+		fields = append(fields, FieldError{Field: "prefecture", Message: "prefecture must be a code from 01 to 47"})
+	}
+	a.Phone = strings.ReplaceAll(strings.TrimSpace(a.Phone), "-", "")
+	if !phonePattern.MatchString(a.Phone) {
+		fields = append(fields, FieldError{Field: "phone", Message: "phone must be a Japanese number, e.g. 090-1234-5678"})
+	}
+
+	if len(fields) > 0 {
+		return &Error{Kind: ErrValidation, Message: "invalid address", Fields: fields}
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hackathon-backend/dao"
+	"hackathon-backend/model"
+	"hackathon-backend/pkg/retry"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	aiJobMaxAttempts  = 5
+	aiJobRetryBase    = 5 * time.Second
+	aiJobRetryMax     = 5 * time.Minute
+	aiJobPollInterval = 2 * time.Second
+)
+
+// aiJobTimeout covers one Gemini call plus saving the result. A job running for
+// twice that is assumed dead (its worker crashed) and is claimed again.
+func aiJobTimeout() time.Duration {
+	return deadlines.LLM + deadlines.Write
+}
+
+// AIJobHandler runs one job. It must mark the job finished (tx.Jobs.Finish) in
+// the same transaction that stores its output; returning an error retries it.
+type AIJobHandler func(ctx context.Context, job *model.AIJob) error
+
+// errJobPermanent wraps failures that retrying can't fix.
+type errJobPermanent struct{ err error }
+
+func (e errJobPermanent) Error() string { return e.err.Error() }
+
+// AIJobQueue is a DB-backed queue of AI reply generations worked by a pool of goroutines.
+type AIJobQueue struct {
+	repo     *dao.AIJobRepository
+	handlers map[string]AIJobHandler
+	wake     chan struct{}
+}
+
+func NewAIJobQueue(repo *dao.AIJobRepository) *AIJobQueue {
+	return &AIJobQueue{repo: repo, handlers: make(map[string]AIJobHandler), wake: make(chan struct{}, 1)}
+}
+
+// Handle registers the handler for a job kind. Call before Run.
+func (q *AIJobQueue) Handle(kind string, handler AIJobHandler) {
+	q.handlers[kind] = handler
+}
+
+// newAIJob builds a queued job for a trigger message; store it with tx.Jobs.Create.
+func newAIJob(kind string, msg *model.Message) *model.AIJob {
+	now := time.Now()
+	return &model.AIJob{
+		ID:            ulid.MustNew(ulid.Now(), ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)).String(),
+		Kind:          kind,
+		ItemID:        msg.ItemID,
+		BuyerID:       msg.BuyerID,
+		MessageID:     msg.ID,
+		Status:        model.JobQueued,
+		MaxAttempts:   aiJobMaxAttempts,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// Wake tells idle workers a job was enqueued. Safe to call on a nil queue.
+func (q *AIJobQueue) Wake() {
+	if q == nil {
+		return
+	}
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run starts workers goroutines and blocks until ctx is cancelled and they have
+// finished their current job.
+func (q *AIJobQueue) Run(ctx context.Context, workers int) {
+	if workers < 1 {
+		workers = 1
+	}
+	jobs := make(chan model.AIJob)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				// A job in flight runs to completion (or its timeout) even when shutdown begins
+				q.process(context.WithoutCancel(ctx), &job)
+			}
+		}()
+	}
+
+	ticker := time.NewTicker(aiJobPollInterval)
+	defer ticker.Stop()
+	for ctx.Err() == nil {
+		now := time.Now()
+		claimed, err := q.repo.Claim(ctx, now, now.Add(2*aiJobTimeout()), workers)
+		if err != nil {
+			fmt.Println("Failed to claim AI jobs:", err)
+		}
+		for _, job := range claimed {
+			select {
+			case jobs <- job:
+			case <-ctx.Done():
+			}
+		}
+		// Poll again right away while the queue is busy
+		if len(claimed) == workers {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+		case <-q.wake:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+func (q *AIJobQueue) process(ctx context.Context, job *model.AIJob) {
+	handler, ok := q.handlers[job.Kind]
+	if !ok {
+		q.fail(ctx, job, errJobPermanent{fmt.Errorf("no handler for job kind %q", job.Kind)})
+		return
+	}
+
+	jobCtx, cancel := withDeadline(ctx, aiJobTimeout())
+	err := runAIJob(jobCtx, handler, job)
+	cancel()
+	if err != nil {
+		q.fail(ctx, job, err)
+	}
+}
+
+// runAIJob runs the handler, turning a panic into a failed attempt.
+func runAIJob(ctx context.Context, handler AIJobHandler, job *model.AIJob) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return handler(ctx, job)
+}
+
+func (q *AIJobQueue) fail(ctx context.Context, job *model.AIJob, err error) {
+	fmt.Printf("AI job %s (%s) attempt %d failed: %v\n", job.ID, job.Kind, job.Attempts, err)
+	var permanent errJobPermanent
+	if errors.As(err, &permanent) || job.Attempts >= job.MaxAttempts {
+		if err := q.repo.Finish(ctx, job.ID, model.JobFailed, "", err.Error()); err != nil {
+			fmt.Println("Failed to mark AI job failed:", err)
+		}
+		return
+	}
+	next := time.Now().Add(retry.Backoff(job.Attempts, aiJobRetryBase, aiJobRetryMax))
+	if err := q.repo.Retry(ctx, job.ID, next, err.Error()); err != nil {
+		fmt.Println("Failed to reschedule AI job:", err)
+	}
+}
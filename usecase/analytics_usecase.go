@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"context"
+	"hackathon-backend/dao"
+	"hackathon-backend/model"
+	"time"
+)
+
+type AnalyticsUsecase struct {
+	repo *dao.AnalyticsRepository
+}
+
+func NewAnalyticsUsecase(repo *dao.AnalyticsRepository) *AnalyticsUsecase {
+	return &AnalyticsUsecase{repo: repo}
+}
+
+// HandleEvent is the domain event subscriber that counts events per day.
+func (u *AnalyticsUsecase) HandleEvent(ctx context.Context, e *model.DomainEvent) error {
+	return u.repo.IncrementDaily(ctx, e.CreatedAt, e.Type)
+}
+
+// GetDailyCounts returns the event counts of the last days days (including today).
+func (u *AnalyticsUsecase) GetDailyCounts(ctx context.Context, days int) ([]model.DailyEventCount, error) {
+	ctx, cancel := withDeadline(ctx, deadlines.Read)
+	defer cancel()
+	if days <= 0 || days > 365 {
+		days = 30
+	}
+	since := time.Now().AddDate(0, 0, -(days - 1))
+	return u.repo.GetDailyCounts(ctx, since)
+}
@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"hackathon-backend/dao"
+	"hackathon-backend/model"
+	"strings"
+)
+
+type CategoryUsecase struct {
+	repo *dao.CategoryRepository
+}
+
+func NewCategoryUsecase(repo *dao.CategoryRepository) *CategoryUsecase {
+	return &CategoryUsecase{repo: repo}
+}
+
+// GetTree returns the top-level categories with their subcategories nested.
+func (u *CategoryUsecase) GetTree(ctx context.Context) ([]*model.Category, error) {
+	ctx, cancel := withDeadline(ctx, deadlines.Read)
+	defer cancel()
+	categories, err := loadCategories(ctx, u.repo)
+	if err != nil {
+		return nil, err
+	}
+	return categories.roots, nil
+}
+
+// GetCategory returns a category with the full attribute schema its items
+// use: the attributes of every ancestor, then its own.
+func (u *CategoryUsecase) GetCategory(ctx context.Context, id string) (*model.Category, error) {
+	ctx, cancel := withDeadline(ctx, deadlines.Read)
+	defer cancel()
+	categories, err := loadCategories(ctx, u.repo)
+	if err != nil {
+		return nil, err
+	}
+	c, ok := categories.byID[id]
+	if !ok {
+		return nil, notFound("category")
+	}
+	category := *c
+	category.Attributes = categories.attributes(id)
+	return &category, nil
+}
+
+// categoryIndex is the category tree loaded in memory; it is small.
+type categoryIndex struct {
+	byID  map[string]*model.Category
+	roots []*model.Category
+}
+
+func loadCategories(ctx context.Context, repo *dao.CategoryRepository) (*categoryIndex, error) {
+	flat, err := repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	idx := &categoryIndex{byID: make(map[string]*model.Category, len(flat))}
+	for i := range flat {
+		idx.byID[flat[i].ID] = &flat[i]
+	}
+	// flat is in display order, so children keep it
+	for i := range flat {
+		c := &flat[i]
+		if c.ParentID == nil {
+			idx.roots = append(idx.roots, c)
+		} else if parent, ok := idx.byID[*c.ParentID]; ok {
+			parent.Children = append(parent.Children, c)
+		}
+	}
+	return idx, nil
+}
+
+// path returns the category's ancestors and itself, root first.
+func (idx *categoryIndex) path(id string) []*model.Category {
+	var path []*model.Category
+	c, ok := idx.byID[id]
+	for ok && len(path) < len(idx.byID) { // The bound stops at a parent cycle
+		path = append([]*model.Category{c}, path...)
+		if c.ParentID == nil {
+			break
+		}
+		c, ok = idx.byID[*c.ParentID]
+	}
+	return path
+}
+
+// pathName is the category path for display, e.g. "家電・スマホ・カメラ > スマートフォン".
+func (idx *categoryIndex) pathName(id string) string {
+	var names []string
+	for _, c := range idx.path(id) {
+		names = append(names, c.Name)
+	}
+	return strings.Join(names, " > ")
+}
+
+// attributes is the attribute schema of items in the category. A subcategory
+// spec replaces an inherited one with the same key.
+func (idx *categoryIndex) attributes(id string) []model.AttributeSpec {
+	var specs []model.AttributeSpec
+	for _, c := range idx.path(id) {
+		for _, spec := range c.Attributes {
+			replaced := false
+			for i := range specs {
+				if specs[i].Key == spec.Key {
+					specs[i], replaced = spec, true
+				}
+			}
+			if !replaced {
+				specs = append(specs, spec)
+			}
+		}
+	}
+	return specs
+}
+
+// subtree lists the category and every category below it.
+func (idx *categoryIndex) subtree(id string) []string {
+	c, ok := idx.byID[id]
+	if !ok {
+		return nil
+	}
+	ids := []string{c.ID}
+	for _, child := range c.Children {
+		ids = append(ids, idx.subtree(child.ID)...)
+	}
+	return ids
+}
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+	"time"
+)
+
+// Deadlines bounds how long each kind of operation may run. They apply on top of
+// the caller's context, so a client disconnect still cancels the work earlier.
+// Zero means no limit of its own.
+type Deadlines struct {
+	Read  time.Duration // A read-only usecase: its queries together
+	Write time.Duration // A usecase that changes state, including its transaction
+	LLM   time.Duration // One Gemini call
+}
+
+// deadlines is replaced from the configuration at startup.
+var deadlines = Deadlines{
+	Read:  5 * time.Second,
+	Write: 10 * time.Second,
+	LLM:   45 * time.Second,
+}
+
+// SetDeadlines replaces the operation deadlines. Call it once at startup, before serving.
+func SetDeadlines(d Deadlines) {
+	deadlines = d
+}
+
+func withDeadline(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d)
+}
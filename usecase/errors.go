@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error kinds. Test for them with errors.Is; the controllers map each one to an
+// HTTP status. Errors of no kind (database failures and the like) are internal.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrUnauthorized = errors.New("unauthorized")      // No caller identity (user_id)
+	ErrForbidden    = errors.New("forbidden")         // The caller may not do this
+	ErrConflict     = errors.New("conflict")          // Not possible in the resource's current state
+	ErrValidation   = errors.New("validation failed") // Bad input; see Error.Fields
+	ErrUpstreamAI   = errors.New("AI service unavailable")
+	ErrUnavailable  = errors.New("feature not configured") // Turned off in this deployment
+
+	ErrVersionRequired = errors.New("version required")      // An update must say which version it is based on
+	ErrVersionMismatch = errors.New("resource was modified") // The update is based on a stale version
+)
+
+// FieldError describes one invalid input field.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is a domain error: its kind, a message safe to show to clients and,
+// for validation failures, the offending fields.
+type Error struct {
+	Kind    error
+	Message string
+	Fields  []FieldError
+	Err     error // Underlying cause, for logs only
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Err != nil {
+		return []error{e.Kind, e.Err}
+	}
+	return []error{e.Kind}
+}
+
+func notFound(what string) error {
+	return &Error{Kind: ErrNotFound, Message: what + " not found"}
+}
+
+func unauthorized() error {
+	return &Error{Kind: ErrUnauthorized, Message: "user_id required"}
+}
+
+func forbidden(message string) error {
+	return &Error{Kind: ErrForbidden, Message: message}
+}
+
+func conflict(message string) error {
+	return &Error{Kind: ErrConflict, Message: message}
+}
+
+// invalid reports a single bad field.
+func invalid(field string, message string) error {
+	return &Error{Kind: ErrValidation, Message: message, Fields: []FieldError{{Field: field, Message: message}}}
+}
+
+func versionMismatch(current int) error {
+	return &Error{Kind: ErrVersionMismatch, Message: fmt.Sprintf("item was modified: current version is %d", current)}
+}
+
+func upstreamAI(err error) error {
+	return &Error{Kind: ErrUpstreamAI, Message: ErrUpstreamAI.Error(), Err: err}
+}
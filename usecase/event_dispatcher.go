@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hackathon-backend/dao"
+	"hackathon-backend/model"
+	"hackathon-backend/pkg/retry"
+	"math/rand"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	eventRetryBase    = 5 * time.Second
+	eventRetryMax     = time.Hour
+	eventLease        = time.Minute
+	eventPollInterval = 2 * time.Second
+	eventBatchSize    = 50
+)
+
+// EventHandler processes one domain event. Returning an error retries the
+// event later for this subscriber only; handlers must tolerate redelivery.
+type EventHandler func(ctx context.Context, e *model.DomainEvent) error
+
+type eventSubscription struct {
+	name    string
+	types   map[string]bool // Empty means every event type
+	handler EventHandler
+}
+
+// EventDispatcher delivers outbox events to in-process subscribers, at least once each.
+type EventDispatcher struct {
+	repo *dao.EventRepository
+	subs []eventSubscription
+	wake chan struct{}
+}
+
+func NewEventDispatcher(repo *dao.EventRepository) *EventDispatcher {
+	return &EventDispatcher{repo: repo, wake: make(chan struct{}, 1)}
+}
+
+// Subscribe registers a handler under a stable name (it keys the delivery
+// record, so renaming a subscriber replays pending events to it).
+// Call before Run.
+func (d *EventDispatcher) Subscribe(name string, handler EventHandler, types ...string) {
+	sub := eventSubscription{name: name, types: make(map[string]bool), handler: handler}
+	for _, t := range types {
+		sub.types[t] = true
+	}
+	d.subs = append(d.subs, sub)
+}
+
+// Wake asks the dispatcher to poll now instead of at the next tick; call after
+// committing events. Safe to call on a nil dispatcher.
+func (d *EventDispatcher) Wake() {
+	if d == nil {
+		return
+	}
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run dispatches events until ctx is cancelled.
+func (d *EventDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(eventPollInterval)
+	defer ticker.Stop()
+	for {
+		d.dispatchDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+func (d *EventDispatcher) dispatchDue(ctx context.Context) {
+	now := time.Now()
+	due, err := d.repo.ClaimDue(ctx, now, now.Add(eventLease), eventBatchSize)
+	if err != nil {
+		fmt.Println("Failed to load domain events:", err)
+		return
+	}
+	for i := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		// An event in flight is finished even when shutdown begins
+		d.dispatch(context.WithoutCancel(ctx), &due[i])
+	}
+}
+
+func (d *EventDispatcher) dispatch(ctx context.Context, e *model.DomainEvent) {
+	handled, err := d.repo.HandledBy(ctx, e.ID)
+	if err != nil {
+		fmt.Println("Failed to load event consumers:", err)
+		return // The lease expires and the event is retried
+	}
+
+	var failure error
+	for _, sub := range d.subs {
+		if handled[sub.name] || (len(sub.types) > 0 && !sub.types[e.Type]) {
+			continue
+		}
+		if err := d.handle(ctx, sub, e); err != nil {
+			failure = fmt.Errorf("%s: %w", sub.name, err)
+			fmt.Printf("Event %s (%s) failed in %v\n", e.ID, e.Type, failure)
+			continue
+		}
+		if err := d.repo.MarkHandled(ctx, e.ID, sub.name); err != nil {
+			failure = err
+		}
+	}
+
+	if failure == nil {
+		if err := d.repo.MarkDispatched(ctx, e.ID, time.Now()); err != nil {
+			fmt.Println("Failed to mark event dispatched:", err)
+		}
+		return
+	}
+	e.Attempts++
+	next := time.Now().Add(retry.Backoff(e.Attempts, eventRetryBase, eventRetryMax))
+	if err := d.repo.MarkFailed(ctx, e.ID, e.Attempts, next, failure.Error()); err != nil {
+		fmt.Println("Failed to reschedule event:", err)
+	}
+}
+
+// handle runs one subscriber, turning a panic into a retryable error.
+func (d *EventDispatcher) handle(ctx context.Context, sub eventSubscription, e *model.DomainEvent) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return sub.handler(ctx, e)
+}
+
+// emitEvent appends a domain event to the outbox inside the caller's transaction.
+func emitEvent(ctx context.Context, tx *dao.Tx, eventType string, aggregateID string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	return tx.Events.Append(ctx, &model.DomainEvent{
+		ID:            ulid.MustNew(ulid.Now(), ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)).String(),
+		Type:          eventType,
+		AggregateID:   aggregateID,
+		Payload:       data,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	})
+}
+
+func decodeItemEvent(e *model.DomainEvent) (*model.ItemEvent, error) {
+	var ev model.ItemEvent
+	if err := json.Unmarshal(e.Payload, &ev); err != nil {
+		return nil, err
+	}
+	return &ev, nil
+}
+
+func decodePriceChangedEvent(e *model.DomainEvent) (*model.PriceChangedEvent, error) {
+	var ev model.PriceChangedEvent
+	if err := json.Unmarshal(e.Payload, &ev); err != nil {
+		return nil, err
+	}
+	return &ev, nil
+}
+
+func decodeCaseEvent(e *model.DomainEvent) (*model.CaseEvent, error) {
+	var ev model.CaseEvent
+	if err := json.Unmarshal(e.Payload, &ev); err != nil {
+		return nil, err
+	}
+	return &ev, nil
+}
+
+func decodeMessageEvent(e *model.DomainEvent) (*model.MessageEvent, error) {
+	var ev model.MessageEvent
+	if err := json.Unmarshal(e.Payload, &ev); err != nil {
+		return nil, err
+	}
+	return &ev, nil
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"hackathon-backend/model"
+	"time"
+)
+
+// itemSummary is the item shape sent outside the app (webhooks, SSE); it leaves out seller-private pricing.
+type itemSummary struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	Price    int     `json:"price"`
+	Status   string  `json:"status"`
+	SellerID string  `json:"seller_id"`
+	BuyerID  *string `json:"buyer_id,omitempty"`
+	ImageURL string  `json:"image_url,omitempty"`
+}
+
+// messageSummary is the message shape sent outside the app (webhooks, SSE).
+type messageSummary struct {
+	ID             string    `json:"id"`
+	ItemID         string    `json:"item_id"`
+	BuyerID        string    `json:"buyer_id,omitempty"`
+	Channel        string    `json:"channel"`
+	SenderID       string    `json:"sender_id"`
+	Content        string    `json:"content"`
+	IsAIResponse   bool      `json:"is_ai_response"`
+	IsApproved     bool      `json:"is_approved"`
+	SuggestedPrice *int      `json:"suggested_price,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// priceChangeSummary is the item.price_changed shape sent outside the app.
+type priceChangeSummary struct {
+	Item     itemSummary `json:"item"`
+	OldPrice int         `json:"old_price"`
+	NewPrice int         `json:"new_price"`
+	Source   string      `json:"source"`
+}
+
+// caseSummary is the order.case_* shape sent outside the app; it goes only to the two parties.
+type caseSummary struct {
+	Case model.OrderCase `json:"case"`
+	Item itemSummary     `json:"item"`
+}
+
+func toItemSummary(item *model.Item) itemSummary {
+	return itemSummary{
+		ID:       item.ID,
+		Name:     item.Name,
+		Price:    item.Price,
+		Status:   item.Status,
+		SellerID: item.UserID,
+		BuyerID:  item.BuyerID,
+		ImageURL: item.ImageURL,
+	}
+}
+
+func toMessageSummary(msg *model.Message) messageSummary {
+	return messageSummary{
+		ID:             msg.ID,
+		ItemID:         msg.ItemID,
+		BuyerID:        msg.BuyerID,
+		Channel:        msg.Channel,
+		SenderID:       msg.SenderID,
+		Content:        msg.Content,
+		IsAIResponse:   msg.IsAIResponse,
+		IsApproved:     msg.IsApproved,
+		SuggestedPrice: msg.SuggestedPrice,
+		CreatedAt:      msg.CreatedAt,
+	}
+}
@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"hackathon-backend/model"
+	"hackathon-backend/pkg/gemini"
+)
+
+// conditionLabels describe each condition to the model.
+var conditionLabels = map[string]string{
+	model.ConditionNew:     "new, unused",
+	model.ConditionLikeNew: "like new",
+	model.ConditionGood:    "good, light signs of use",
+	model.ConditionFair:    "fair, visible wear",
+	model.ConditionPoor:    "poor, damaged or heavily worn",
+}
+
+// promptDetails describes the item's classification for the negotiation
+// prompt. Failures only cost context.
+func (u *ItemUsecase) promptDetails(ctx context.Context, item *model.Item) gemini.ItemDetails {
+	details := gemini.ItemDetails{Brand: item.Brand, Condition: conditionLabels[item.Condition]}
+	if item.CategoryID == nil {
+		return details
+	}
+	categories, err := loadCategories(ctx, u.categoryRepo)
+	if err != nil {
+		fmt.Println("Failed to load categories:", err)
+		return details
+	}
+	details.Category = categories.pathName(*item.CategoryID)
+	for _, spec := range categories.attributes(*item.CategoryID) {
+		if value := item.Attributes[spec.Key]; value != "" {
+			details.Attributes = append(details.Attributes, gemini.ItemAttribute{Label: spec.Label, Value: value})
+		}
+	}
+	return details
+}
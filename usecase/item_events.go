@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"hackathon-backend/dao"
+	"hackathon-backend/model"
+)
+
+// ------ Domain events ------
+
+// counterpartOf is the buyer side of a message's conversation: the sender if a
+// buyer wrote it, otherwise the thread buyer or the asker of a public question.
+func counterpartOf(ctx context.Context, msgs *dao.MessageRepository, item *model.Item, msg *model.Message) string {
+	if msg.SenderID != item.UserID {
+		return msg.SenderID
+	}
+	if msg.Channel != model.ChannelPublic {
+		return msg.BuyerID
+	}
+	if msg.ReplyToID == "" {
+		return ""
+	}
+	question, err := msgs.GetMessageByID(ctx, msg.ReplyToID)
+	if err != nil {
+		return ""
+	}
+	return question.SenderID
+}
+
+func emitMessageEvent(ctx context.Context, tx *dao.Tx, eventType string, item *model.Item, msg *model.Message) error {
+	return emitEvent(ctx, tx, eventType, item.ID, model.MessageEvent{
+		ItemID:        item.ID,
+		ItemName:      item.Name,
+		SellerID:      item.UserID,
+		CounterpartID: counterpartOf(ctx, tx.Messages, item, msg),
+		Message:       *msg,
+	})
+}
+
+// emitDraftEvents records a new AI message and whether it waits for the seller or went out directly.
+func emitDraftEvents(ctx context.Context, tx *dao.Tx, item *model.Item, aiMsg *model.Message) error {
+	if err := emitMessageEvent(ctx, tx, model.EventMessageCreated, item, aiMsg); err != nil {
+		return err
+	}
+	if aiMsg.IsApproved {
+		return emitMessageEvent(ctx, tx, model.EventDraftApproved, item, aiMsg)
+	}
+	return emitMessageEvent(ctx, tx, model.EventDraftPending, item, aiMsg)
+}
+
+// inTx runs fn in a transaction, bounded by the write deadline, and once committed
+// wakes the event dispatcher so the events it emitted go out right away.
+func (u *ItemUsecase) inTx(ctx context.Context, fn func(tx *dao.Tx) error) error {
+	ctx, cancel := withDeadline(ctx, deadlines.Write)
+	defer cancel()
+	if err := u.txm.WithTx(ctx, fn); err != nil {
+		return err
+	}
+	u.events.Wake()
+	return nil
+}
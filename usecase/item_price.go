@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"hackathon-backend/dao"
+	"hackathon-backend/model"
+	"hackathon-backend/pkg/gemini"
+	"math/rand"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// promptPriceHistory caps how many recent price changes Smart-Nego sees.
+const promptPriceHistory = 10
+
+// recordPriceChange appends to the item's price history and, for a change of
+// an existing price, emits item.price_changed. Call it in the transaction that
+// changed the price; oldPrice is nil for the listing price.
+func recordPriceChange(ctx context.Context, tx *dao.Tx, item *model.Item, oldPrice *int, source string, changedBy string, messageID *string) error {
+	if oldPrice != nil && *oldPrice == item.Price {
+		return nil
+	}
+	err := tx.Prices.Insert(ctx, &model.PriceChange{
+		ID:        ulid.MustNew(ulid.Now(), ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)).String(),
+		ItemID:    item.ID,
+		OldPrice:  oldPrice,
+		NewPrice:  item.Price,
+		Source:    source,
+		ChangedBy: changedBy,
+		MessageID: messageID,
+		CreatedAt: time.Now(),
+	})
+	if err != nil || oldPrice == nil {
+		return err
+	}
+	return emitEvent(ctx, tx, model.EventPriceChanged, item.ID, model.PriceChangedEvent{
+		Item:     *item,
+		OldPrice: *oldPrice,
+		NewPrice: item.Price,
+		Source:   source,
+	})
+}
+
+// GetPriceHistory lists every price the item has had, oldest first.
+func (u *ItemUsecase) GetPriceHistory(ctx context.Context, itemID string) (*model.PriceHistory, error) {
+	ctx, cancel := withDeadline(ctx, deadlines.Read)
+	defer cancel()
+	item, err := u.itemRepo.GetByID(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil || item.Status == "deleted" {
+		return nil, notFound("item")
+	}
+	changes, err := u.priceRepo.GetByItemID(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &model.PriceHistory{
+		ItemID:       item.ID,
+		InitialPrice: item.InitialPrice,
+		CurrentPrice: item.Price,
+		LowestPrice:  item.Price,
+		Changes:      changes,
+	}
+	if history.Changes == nil {
+		history.Changes = []model.PriceChange{}
+	}
+	for _, c := range changes {
+		if c.NewPrice < history.LowestPrice {
+			history.LowestPrice = c.NewPrice
+		}
+	}
+	if len(changes) > 0 {
+		history.DropFromFirst = changes[0].NewPrice - item.Price
+	}
+	return history, nil
+}
+
+// priceHistoryEntries loads the latest price changes in the shape the prompts
+// expect. Failures only cost context.
+func (u *ItemUsecase) priceHistoryEntries(ctx context.Context, itemID string) []gemini.PriceChange {
+	changes, err := u.priceRepo.GetByItemID(ctx, itemID)
+	if err != nil {
+		fmt.Println("Failed to load price history:", err)
+		return nil
+	}
+	if len(changes) > promptPriceHistory {
+		changes = changes[len(changes)-promptPriceHistory:]
+	}
+	var entries []gemini.PriceChange
+	for _, c := range changes {
+		if c.OldPrice == nil {
+			continue // The listing price is already in the prompt as the initial price
+		}
+		entries = append(entries, gemini.PriceChange{
+			Date:     c.CreatedAt.Format("2006-01-02"),
+			OldPrice: *c.OldPrice,
+			NewPrice: c.NewPrice,
+			Source:   priceSourceLabels[c.Source],
+		})
+	}
+	return entries
+}
+
+// priceSourceLabels describe each source to the model.
+var priceSourceLabels = map[string]string{
+	model.PriceSourceSellerEdit:  "seller edited the listing",
+	model.PriceSourceNegotiation: "agreed in a negotiation",
+	model.PriceSourceRevoke:      "seller withdrew a negotiated price",
+	model.PriceSourcePromotion:   "promotion",
+}
@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"hackathon-backend/dao"
+	"hackathon-backend/model"
+	"hackathon-backend/pkg/gemini"
+	"math/rand"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// ------ Public Q&A / FAQ ------
+
+// GetFAQ returns the approved public Q&A pairs of an item.
+func (u *ItemUsecase) GetFAQ(ctx context.Context, itemID string) ([]model.ItemFAQ, error) {
+	ctx, cancel := withDeadline(ctx, deadlines.Read)
+	defer cancel()
+	item, err := u.itemRepo.GetByID(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, notFound("item")
+	}
+	return u.faqRepo.GetByItemID(ctx, itemID)
+}
+
+// faqEntries loads the FAQ in the shape the prompts expect. Failures only cost context.
+func (u *ItemUsecase) faqEntries(ctx context.Context, itemID string) []gemini.FAQEntry {
+	faqs, err := u.faqRepo.GetByItemID(ctx, itemID)
+	if err != nil {
+		fmt.Println("Failed to load FAQ:", err)
+		return nil
+	}
+	var entries []gemini.FAQEntry
+	for _, f := range faqs {
+		entries = append(entries, gemini.FAQEntry{Question: f.Question, Answer: f.Answer})
+	}
+	return entries
+}
+
+// validateReplyTo checks a public answer points at a public message of the same item.
+func (u *ItemUsecase) validateReplyTo(ctx context.Context, itemID string, replyToID string) error {
+	question, err := u.msgRepo.GetMessageByID(ctx, replyToID)
+	if err != nil || question.ItemID != itemID || question.Channel != model.ChannelPublic {
+		return invalid("reply_to_id", "invalid reply_to_id")
+	}
+	return nil
+}
+
+// ProcessPublicAnswerJob drafts the answer to a visitor's public question. It
+// runs on the AI worker pool; returning an error retries the job.
+func (u *ItemUsecase) ProcessPublicAnswerJob(ctx context.Context, job *model.AIJob) error {
+	item, err := u.itemRepo.GetByID(ctx, job.ItemID)
+	if err != nil {
+		return err
+	}
+	if item == nil || !item.AINegotiationEnabled {
+		return u.finishJob(ctx, job, model.JobSkipped, "AI answers not enabled")
+	}
+	question, err := u.msgRepo.GetMessageByID(ctx, job.MessageID)
+	if err != nil {
+		return u.finishJob(ctx, job, model.JobSkipped, "message no longer exists")
+	}
+	return u.answerPublicQuestion(ctx, job, item, question)
+}
+
+// answerPublicQuestion drafts an answer strictly from the item description and FAQ.
+// It follows the same approval rules as negotiation ANSWER drafts, except that
+// an answer the model could not ground in the listing always waits for the
+// seller, so it never reaches the FAQ unreviewed.
+func (u *ItemUsecase) answerPublicQuestion(ctx context.Context, job *model.AIJob, item *model.Item, question *model.Message) error {
+	llmCtx, cancel := withDeadline(ctx, deadlines.LLM)
+	defer cancel()
+	resp, err := u.geminiClient.GenerateAnswer(llmCtx, gemini.AnswerPromptData{
+		ItemDescription: item.Description,
+		Shipping:        promptShipping(item),
+		FAQ:             u.faqEntries(ctx, item.ID),
+		Question:        question.Content,
+	})
+	if err != nil {
+		return upstreamAI(err)
+	}
+
+	aiMsg := &model.Message{
+		ID:           ulid.MustNew(ulid.Now(), ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)).String(),
+		ItemID:       item.ID,
+		Channel:      model.ChannelPublic,
+		ReplyToID:    question.ID,
+		SenderID:     item.UserID,
+		Content:      resp.ResponseContent,
+		IsAIResponse: true,
+		IsApproved:   resp.Answerable && shouldAutoApprove(item, "ANSWER", nil),
+		CreatedAt:    time.Now(),
+	}
+	negotiationLog := &model.NegotiationLog{
+		ID:           ulid.MustNew(ulid.Now(), ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)).String(),
+		ItemID:       item.ID,
+		UserID:       question.SenderID,
+		MessageID:    aiMsg.ID,
+		AIDecision:   "ANSWER",
+		AIReasoning:  resp.Reasoning,
+		AutoApproved: aiMsg.IsApproved,
+		Outcome:      draftOutcome(aiMsg),
+		LogTime:      time.Now(),
+	}
+	return u.inTx(ctx, func(tx *dao.Tx) error {
+		if err := tx.Messages.CreateMessage(ctx, aiMsg); err != nil {
+			return err
+		}
+		if err := tx.Messages.CreateNegotiationLog(ctx, negotiationLog); err != nil {
+			return err
+		}
+		if aiMsg.IsApproved {
+			if err := recordFAQ(ctx, tx, aiMsg); err != nil {
+				return err
+			}
+		}
+		if err := emitDraftEvents(ctx, tx, item, aiMsg); err != nil {
+			return err
+		}
+		return tx.Jobs.Finish(ctx, job.ID, model.JobSucceeded, aiMsg.ID, "")
+	})
+}
+
+// recordFAQ adds an approved public answer and its question to the item FAQ.
+func recordFAQ(ctx context.Context, tx *dao.Tx, answer *model.Message) error {
+	if answer.Channel != model.ChannelPublic || answer.ReplyToID == "" {
+		return nil
+	}
+	question, err := tx.Messages.GetMessageByID(ctx, answer.ReplyToID)
+	if err != nil {
+		return err
+	}
+	return tx.FAQs.Create(ctx, &model.ItemFAQ{
+		ID:                ulid.MustNew(ulid.Now(), ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)).String(),
+		ItemID:            answer.ItemID,
+		QuestionMessageID: question.ID,
+		AnswerMessageID:   answer.ID,
+		Question:          question.Content,
+		Answer:            answer.Content,
+		CreatedAt:         time.Now(),
+	})
+}
@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hackathon-backend/model"
+	"hackathon-backend/pkg/gemini"
+	"hackathon-backend/pkg/shipping"
+)
+
+// EstimateShipping prices a parcel from the rate table, for sellers choosing
+// their shipping options.
+func (u *ItemUsecase) EstimateShipping(method string, size string, from string, to string) (*model.ShippingRate, error) {
+	cost, err := shipping.Cost(method, size, from, to)
+	if err != nil {
+		return nil, shippingError(err)
+	}
+	return &model.ShippingRate{ShippingMethod: method, SizeClass: size, ShipFrom: from, ShipTo: to, ShippingCost: cost}, nil
+}
+
+// GetShippingQuote is the total a buyer in shipTo would pay for the item now.
+func (u *ItemUsecase) GetShippingQuote(ctx context.Context, itemID string, shipTo string) (*model.ShippingQuote, error) {
+	ctx, cancel := withDeadline(ctx, deadlines.Read)
+	defer cancel()
+	item, err := u.itemRepo.GetByID(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil || item.Status == "deleted" {
+		return nil, notFound("item")
+	}
+	if item.ShippingMethod == "" {
+		return nil, conflict("the seller has not set shipping options")
+	}
+	return quoteShipping(item, shipTo)
+}
+
+// quoteShipping prices the item's shipping to shipTo. When the seller pays, the
+// destination doesn't change what the buyer pays and may be left empty.
+func quoteShipping(item *model.Item, shipTo string) (*model.ShippingQuote, error) {
+	quote := &model.ShippingQuote{
+		ShippingRate: model.ShippingRate{
+			ShippingMethod: item.ShippingMethod,
+			SizeClass:      item.SizeClass,
+			ShipFrom:       item.ShipFrom,
+			ShipTo:         shipTo,
+		},
+		ShippingPayer: item.ShippingPayer,
+		DaysToShip:    item.DaysToShip,
+		ItemPrice:     item.Price,
+	}
+	if shipTo == "" {
+		if item.ShippingPayer == shipping.PayerBuyer {
+			return nil, invalid("ship_to", "ship_to is required: the buyer pays shipping")
+		}
+		quote.Total = item.Price
+		return quote, nil
+	}
+	cost, err := shipping.Cost(item.ShippingMethod, item.SizeClass, item.ShipFrom, shipTo)
+	if err != nil {
+		return nil, shippingError(err)
+	}
+	quote.ShippingCost = cost
+	if item.ShippingPayer == shipping.PayerBuyer {
+		quote.ShippingFee = cost
+	}
+	quote.Total = item.Price + quote.ShippingFee
+	return quote, nil
+}
+
+// shippingError reports a rate table miss against the field that caused it.
+func shippingError(err error) error {
+	switch {
+	case errors.Is(err, shipping.ErrUnknownMethod):
+		return invalid("shipping_method", "shipping_method must be one of rakuraku, yuyu, standard")
+	case errors.Is(err, shipping.ErrUnsupportedSize):
+		return invalid("size_class", err.Error())
+	case errors.Is(err, shipping.ErrUnknownPrefecture):
+		return invalid("prefecture", "prefectures are codes from 01 to 47")
+	}
+	return err
+}
+
+// Descriptions of the shipping options for the prompts.
+var (
+	shippingMethodLabels = map[string]string{
+		shipping.MethodRakuraku: "Rakuraku courier (anonymous, tracked)",
+		shipping.MethodYuyu:     "Yuyu post office parcel (anonymous, tracked)",
+		shipping.MethodStandard: "regular courier",
+	}
+	shippingPayerLabels = map[string]string{
+		shipping.PayerSeller: "included in the price (the seller pays)",
+		shipping.PayerBuyer:  "paid by the buyer on top of the price",
+	}
+)
+
+// promptShipping describes the item's shipping for the prompts, or nil if the
+// seller hasn't set it.
+func promptShipping(item *model.Item) *gemini.ShippingInfo {
+	if item.ShippingMethod == "" {
+		return nil
+	}
+	info := &gemini.ShippingInfo{
+		Method:     shippingMethodLabels[item.ShippingMethod],
+		Size:       item.SizeClass,
+		ShipFrom:   shipping.PrefectureName(item.ShipFrom),
+		DaysToShip: item.DaysToShip,
+		Payer:      shippingPayerLabels[item.ShippingPayer],
+	}
+	low, high, err := shipping.CostRange(item.ShippingMethod, item.SizeClass, item.ShipFrom)
+	switch {
+	case err != nil:
+		info.Cost = "unknown"
+	case low == high:
+		info.Cost = fmt.Sprintf("¥%d anywhere in Japan", low)
+	default:
+		info.Cost = fmt.Sprintf("¥%d to ¥%d depending on the destination", low, high)
+	}
+	return info
+}
@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"errors"
+	"hackathon-backend/model"
+	"hackathon-backend/pkg/shipping"
+	"testing"
+)
+
+func TestQuoteShipping(t *testing.T) {
+	item := func(method, size, payer string) *model.Item {
+		it := &model.Item{Price: 3000}
+		it.ShippingOptions = model.ShippingOptions{ShippingMethod: method, SizeClass: size, ShippingPayer: payer, ShipFrom: "13", DaysToShip: "1-2"}
+		return it
+	}
+
+	tests := []struct {
+		name      string
+		item      *model.Item
+		shipTo    string
+		cost, fee int
+		total     int
+		errField  string
+	}{
+		{name: "flat, buyer pays", item: item(shipping.MethodRakuraku, "60", shipping.PayerBuyer), shipTo: "47", cost: 750, fee: 750, total: 3750},
+		{name: "flat, seller pays", item: item(shipping.MethodRakuraku, "60", shipping.PayerSeller), shipTo: "47", cost: 750, total: 3000},
+		{name: "zoned, buyer pays", item: item(shipping.MethodStandard, "60", shipping.PayerBuyer), shipTo: "01", cost: 1160, fee: 1160, total: 4160},
+		{name: "zoned, seller pays", item: item(shipping.MethodStandard, "60", shipping.PayerSeller), shipTo: "01", cost: 1160, total: 3000},
+		{name: "seller pays, no destination", item: item(shipping.MethodStandard, "60", shipping.PayerSeller), total: 3000},
+		{name: "buyer pays, no destination", item: item(shipping.MethodStandard, "60", shipping.PayerBuyer), errField: "ship_to"},
+		{name: "unsupported size", item: item(shipping.MethodStandard, shipping.SizeSmall, shipping.PayerBuyer), shipTo: "13", errField: "size_class"},
+		{name: "unknown destination", item: item(shipping.MethodYuyu, "60", shipping.PayerBuyer), shipTo: "99", errField: "prefecture"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote, err := quoteShipping(tt.item, tt.shipTo)
+			if tt.errField != "" {
+				var e *Error
+				if !errors.As(err, &e) || len(e.Fields) != 1 || e.Fields[0].Field != tt.errField {
+					t.Fatalf("err = %v, want a validation error on %s", err, tt.errField)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if quote.ShippingCost != tt.cost || quote.ShippingFee != tt.fee || quote.Total != tt.total {
+				t.Errorf("cost/fee/total = %d/%d/%d, want %d/%d/%d", quote.ShippingCost, quote.ShippingFee, quote.Total, tt.cost, tt.fee, tt.total)
+			}
+			if quote.ItemPrice != 3000 || quote.ShipTo != tt.shipTo {
+				t.Errorf("quote = %+v", quote)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"hackathon-backend/dao"
 	"hackathon-backend/model"
@@ -17,31 +18,101 @@ import (
 type ItemUsecase struct {
 	itemRepo    *dao.ItemRepository
 	msgRepo     *dao.MessageRepository
+	faqRepo     *dao.FAQRepository
+	userRepo    *dao.UserRepository
+	priceRepo   *dao.PriceHistoryRepository
+	categoryRepo *dao.CategoryRepository
+	addresses   *AddressUsecase
+	msgJobs     *dao.AIJobRepository
+	txm         *dao.TxManager
+	events      *EventDispatcher
+	jobs        *AIJobQueue
 	geminiClient *gemini.Client
 }
 
-func NewItemUsecase(itemRepo *dao.ItemRepository, msgRepo *dao.MessageRepository, geminiClient *gemini.Client) *ItemUsecase {
+func NewItemUsecase(itemRepo *dao.ItemRepository, msgRepo *dao.MessageRepository, faqRepo *dao.FAQRepository, userRepo *dao.UserRepository, priceRepo *dao.PriceHistoryRepository, categoryRepo *dao.CategoryRepository, addresses *AddressUsecase, msgJobs *dao.AIJobRepository, txm *dao.TxManager, events *EventDispatcher, jobs *AIJobQueue, geminiClient *gemini.Client) *ItemUsecase {
 	return &ItemUsecase{
 		itemRepo:     itemRepo,
 		msgRepo:      msgRepo,
+		faqRepo:      faqRepo,
+		userRepo:     userRepo,
+		priceRepo:    priceRepo,
+		categoryRepo: categoryRepo,
+		addresses:    addresses,
+		msgJobs:      msgJobs,
+		txm:          txm,
+		events:       events,
+		jobs:         jobs,
 		geminiClient: geminiClient,
 	}
 }
 
-func (u *ItemUsecase) GetAllItems() ([]model.Item, error) {
-	return u.itemRepo.GetAll()
+// ListItems lists the items that are not deleted. A category filter includes its subcategories.
+func (u *ItemUsecase) ListItems(ctx context.Context, filter model.ItemFilter) ([]model.Item, error) {
+	ctx, cancel := withDeadline(ctx, deadlines.Read)
+	defer cancel()
+	var fields []FieldError
+	for _, c := range filter.Conditions {
+		if !contains(model.ItemConditions, c) {
+			fields = append(fields, FieldError{Field: "condition", Message: "condition must be one of " + strings.Join(model.ItemConditions, ", ")})
+			break
+		}
+	}
+	for key := range filter.Attributes {
+		if !isAttributeKey(key) {
+			fields = append(fields, FieldError{Field: "attr." + key, Message: "unknown attribute"})
+		}
+	}
+	if filter.CategoryID != "" {
+		categories, err := loadCategories(ctx, u.categoryRepo)
+		if err != nil {
+			return nil, err
+		}
+		filter.CategoryIDs = categories.subtree(filter.CategoryID)
+		if len(filter.CategoryIDs) == 0 {
+			fields = append(fields, FieldError{Field: "category", Message: "unknown category"})
+		}
+	}
+	if len(fields) > 0 {
+		return nil, &Error{Kind: ErrValidation, Message: "invalid filter", Fields: fields}
+	}
+	return u.itemRepo.List(ctx, filter)
 }
 
-func (u *ItemUsecase) GetItemByID(id string) (*model.Item, error) {
+// isAttributeKey accepts the keys attribute schemas use: lower case letters, digits and _.
+func isAttributeKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, r := range key {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+func (u *ItemUsecase) GetItemByID(ctx context.Context, id string) (*model.Item, error) {
+    ctx, cancel := withDeadline(ctx, deadlines.Read)
+    defer cancel()
     // This is the public method for "Viewing an Item", so we increment views.
-    if err := u.itemRepo.IncrementViewCount(id); err != nil {
+    if err := u.itemRepo.IncrementViewCount(ctx, id); err != nil {
         // Log error but proceed? Or fail? Best to proceed.
         fmt.Println("Failed to increment views:", err)
     }
-	return u.itemRepo.GetByID(id)
+	item, err := u.itemRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, notFound("item")
+	}
+	return item, nil
 }
 
-func (u *ItemUsecase) CreateItem(name string, price int, description string, userID string, aiEnabled bool, minPrice *int, imageURL string) (*model.Item, error) {
+func (u *ItemUsecase) CreateItem(ctx context.Context, name string, price int, description string, userID string, aiEnabled bool, minPrice *int, imageURL string, autoApproveAnswers bool, autoApproveMinPrice *int, details model.ItemDetails, shippingOptions model.ShippingOptions) (*model.Item, error) {
+	ctx, cancel := withDeadline(ctx, deadlines.Write)
+	defer cancel()
 	entropy := ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)
 	id := ulid.MustNew(ulid.Now(), entropy).String()
 
@@ -56,56 +127,156 @@ func (u *ItemUsecase) CreateItem(name string, price int, description string, use
 		MinPrice:             minPrice,
 		ImageURL:             imageURL,
         InitialPrice:         price, // Set initial price
+		Version:              1,
+		AutoApproveAnswers:   autoApproveAnswers,
+		AutoApproveMinPrice:  autoApproveMinPrice,
+		ItemDetails:          details,
+		ShippingOptions:      shippingOptions,
+	}
+	if err := u.validateNewItem(ctx, item); err != nil {
+		return nil, err
 	}
 
-	if err := u.itemRepo.Insert(item); err != nil {
+	err := u.inTx(ctx, func(tx *dao.Tx) error {
+		if err := tx.Items.Insert(ctx, item); err != nil {
+			return err
+		}
+		if err := recordPriceChange(ctx, tx, item, nil, model.PriceSourceListing, userID, nil); err != nil {
+			return err
+		}
+		return emitEvent(ctx, tx, model.EventItemCreated, item.ID, model.ItemEvent{Item: *item})
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return item, nil
 }
 
-func (u *ItemUsecase) PurchaseItem(itemID string, buyerID string) (*model.Item, error) {
-	item, err := u.itemRepo.GetByID(itemID)
+// validateNewItem checks a listing and that its seller is a registered user.
+func (u *ItemUsecase) validateNewItem(ctx context.Context, item *model.Item) error {
+	if item.UserID == "" {
+		return unauthorized()
+	}
+	_, err := u.userRepo.GetByID(ctx, item.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return invalid("user_id", "unknown user")
+	}
+	if err != nil {
+		return err
+	}
+	categories, err := loadCategories(ctx, u.categoryRepo)
+	if err != nil {
+		return err
+	}
+	return validateItem(item, categories)
+}
+
+// PurchaseItem sells the item to the buyer. It ships to the buyer's address
+// addressID, or their default address; a copy is kept for the seller. Without
+// the address book, shipTo names the destination prefecture. A buyer-paid
+// shipping cost is recorded as the item's ShippingFee.
+func (u *ItemUsecase) PurchaseItem(ctx context.Context, itemID string, buyerID string, addressID string, shipTo string) (*model.Item, error) {
+	if buyerID == "" {
+		return nil, unauthorized()
+	}
+	address, err := u.addresses.purchaseAddress(ctx, buyerID, addressID)
 	if err != nil {
 		return nil, err
 	}
-	if item == nil {
-		return nil, errors.New("item not found")
+	if address != nil {
+		shipTo = address.Prefecture
 	}
+	var item *model.Item
+	err = u.inTx(ctx, func(tx *dao.Tx) error {
+		// Lock the row so two buyers can't both purchase
+		var err error
+		item, err = tx.Items.GetByIDForUpdate(ctx, itemID)
+		if err != nil {
+			return err
+		}
+		if item == nil {
+			return notFound("item")
+		}
 
-    // Block self-purchase
-    if item.UserID == buyerID {
-        return nil, errors.New("cannot buy your own item")
-    }
+        // Block self-purchase
+        if item.UserID == buyerID {
+            return forbidden("cannot buy your own item")
+        }
 
-	if item.Status == "sold" {
-		return nil, errors.New("item already sold")
-	}
+		if item.Status == "sold" {
+			return conflict("item already sold")
+		}
+		if item.Status != "on_sale" {
+			return conflict("item is not on sale")
+		}
+		if item.ShippingMethod != "" && address == nil && u.addresses.Enabled() {
+			return invalid("address_id", "choose a shipping address")
+		}
+		if item.ShippingMethod != "" {
+			quote, err := quoteShipping(item, shipTo)
+			if err != nil {
+				return err
+			}
+			item.ShippingFee = &quote.ShippingFee
+		}
 
-	item.BuyerID = &buyerID
-	item.Status = "sold"
+		now := time.Now()
+		item.BuyerID = &buyerID
+		item.Status = "sold"
+		item.SoldAt = &now
 
-	if err := u.itemRepo.Update(item); err != nil {
+		if err := tx.Items.Update(ctx, item); err != nil {
+			return err
+		}
+		if address != nil {
+			sealed, err := u.addresses.sealPurchase(address, item.ID)
+			if err != nil {
+				return err
+			}
+			if err := tx.Addresses.InsertPurchase(ctx, item.ID, sealed); err != nil {
+				return err
+			}
+		}
+
+		// Everyone else who was negotiating gets told the item is gone
+		threads, err := tx.Messages.GetThreads(ctx, item.UserID, item.ID)
+		if err != nil {
+			return err
+		}
+		var otherBuyers []string
+		for _, t := range threads {
+			if t.BuyerID != buyerID {
+				otherBuyers = append(otherBuyers, t.BuyerID)
+			}
+		}
+		return emitEvent(ctx, tx, model.EventItemSold, item.ID, model.ItemEvent{Item: *item, OtherBuyerIDs: otherBuyers})
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return item, nil
 }
 
-func (u *ItemUsecase) DeleteItem(itemID string, userID string) error {
-	item, err := u.itemRepo.GetByID(itemID)
+func (u *ItemUsecase) DeleteItem(ctx context.Context, itemID string, userID string) error {
+	ctx, cancel := withDeadline(ctx, deadlines.Write)
+	defer cancel()
+	if userID == "" {
+		return unauthorized()
+	}
+	item, err := u.itemRepo.GetByID(ctx, itemID)
 	if err != nil {
 		return err
 	}
 	if item == nil {
-		return errors.New("item not found")
+		return notFound("item")
 	}
     if item.UserID != userID {
-        return errors.New("unauthorized")
+        return forbidden("only the seller can delete this item")
     }
-    if item.Status != "on_sale" {
-        return errors.New("cannot delete item not on sale")
+    if item.Status != "on_sale" && item.Status != model.ItemStatusCancelled {
+        return conflict("cannot delete item not on sale")
     }
 
     // Hard delete or Soft delete?
@@ -119,40 +290,156 @@ func (u *ItemUsecase) DeleteItem(itemID string, userID string) error {
     // Let's just update itemRepo to support Delete or use a specialized status.
     // Wait, the user asked for "Delete". Let's update `item.Status` to "deleted" (assuming string field).
     item.Status = "deleted" 
-    return u.itemRepo.Update(item) // dao must support this status
+    return u.itemRepo.Update(ctx, item) // dao must support this status
 }
 
-func (u *ItemUsecase) UpdateItem(itemID string, userID string, name string, price int, description string, aiEnabled bool, minPrice *int, imageURL string) (*model.Item, error) {
-    item, err := u.itemRepo.GetByID(itemID)
-    if err != nil {
-        return nil, err
-    }
-    if item == nil {
-        return nil, errors.New("item not found")
-    }
-    if item.UserID != userID {
-        return nil, errors.New("unauthorized")
-    }
-    
-    // Update fields
-    item.Name = name
-    item.Price = price
-    item.Description = description
-    item.AINegotiationEnabled = aiEnabled
-    item.MinPrice = minPrice
-    if imageURL != "" { // Only update if provided, or allow clearing? For MVP, assume update if not empty. Or always update.
-        item.ImageURL = imageURL
-    }
-    // If we want to allow clearing image, we need better logic, but for now assuming always passing current or new.
-    item.ImageURL = imageURL
-    
-    // Reset InitialPrice to new Price (User explicitly changed it)
-    item.InitialPrice = price
+// UpdateItem replaces the seller-editable fields of a listing (PUT). An empty
+// imageURL keeps the current image. version 0 skips the concurrency check.
+func (u *ItemUsecase) UpdateItem(ctx context.Context, itemID string, userID string, name string, price int, description string, aiEnabled bool, minPrice *int, imageURL string, autoApproveAnswers bool, autoApproveMinPrice *int, details model.ItemDetails, shippingOptions model.ShippingOptions, version int) (*model.Item, error) {
+	patch := model.ItemPatch{
+		Name:                 &name,
+		Price:                &price,
+		Description:          &description,
+		AINegotiationEnabled: &aiEnabled,
+		MinPrice:             model.NullableInt{Set: true, Value: minPrice},
+		AutoApproveAnswers:   &autoApproveAnswers,
+		AutoApproveMinPrice:  model.NullableInt{Set: true, Value: autoApproveMinPrice},
+		CategoryID:           model.NullableString{Set: true, Value: details.CategoryID},
+		Brand:                &details.Brand,
+		Condition:            &details.Condition,
+		Attributes:           &details.Attributes,
+		ShippingMethod:       &shippingOptions.ShippingMethod,
+		ShippingPayer:        &shippingOptions.ShippingPayer,
+		ShipFrom:             &shippingOptions.ShipFrom,
+		DaysToShip:           &shippingOptions.DaysToShip,
+		SizeClass:            &shippingOptions.SizeClass,
+	}
+	if imageURL != "" {
+		patch.ImageURL = &imageURL
+	}
+	return u.updateItem(ctx, itemID, userID, patch, version)
+}
 
-    if err := u.itemRepo.Update(item); err != nil {
-        return nil, err
-    }
-    return item, nil
+// PatchItem changes only the fields set in patch (PATCH). version is the one the
+// seller last read; the update is refused if the item has moved on since.
+func (u *ItemUsecase) PatchItem(ctx context.Context, itemID string, userID string, patch model.ItemPatch, version int) (*model.Item, error) {
+	if version == 0 {
+		return nil, &Error{Kind: ErrVersionRequired, Message: "send If-Match with the item's ETag, or its version"}
+	}
+	if patch.Empty() {
+		return nil, &Error{Kind: ErrValidation, Message: "nothing to update"}
+	}
+	return u.updateItem(ctx, itemID, userID, patch, version)
+}
+
+func (u *ItemUsecase) updateItem(ctx context.Context, itemID string, userID string, patch model.ItemPatch, version int) (*model.Item, error) {
+	if userID == "" {
+		return nil, unauthorized()
+	}
+	categories, err := loadCategories(ctx, u.categoryRepo)
+	if err != nil {
+		return nil, err
+	}
+	var item *model.Item
+	err = u.inTx(ctx, func(tx *dao.Tx) error {
+		var err error
+		item, err = tx.Items.GetByIDForUpdate(ctx, itemID)
+		if err != nil {
+			return err
+		}
+		if item == nil {
+			return notFound("item")
+		}
+		if item.UserID != userID {
+			return forbidden("only the seller can update this item")
+		}
+		if item.Status != "on_sale" {
+			return conflict("only items on sale can be edited")
+		}
+		if version != 0 && item.Version != version {
+			return versionMismatch(item.Version)
+		}
+
+		oldPrice := item.Price
+		applyItemPatch(item, patch)
+		if err := validateItem(item, categories); err != nil {
+			return err
+		}
+		// The row is locked, so this only fails if the version moved before we read it
+		updated, err := tx.Items.UpdateIfVersion(ctx, item, item.Version)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return versionMismatch(item.Version)
+		}
+		return recordPriceChange(ctx, tx, item, &oldPrice, model.PriceSourceSellerEdit, userID, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// applyItemPatch copies the set fields onto item and applies the initial price rules:
+// InitialPrice is the highest price the item has been listed at, so buyers and
+// Smart-Nego keep seeing a price cut as a discount. Raising the price above it
+// moves it up; only an explicit reset_initial_price starts over at the new price.
+func applyItemPatch(item *model.Item, patch model.ItemPatch) {
+	if patch.Name != nil {
+		item.Name = *patch.Name
+	}
+	if patch.Description != nil {
+		item.Description = *patch.Description
+	}
+	if patch.AINegotiationEnabled != nil {
+		item.AINegotiationEnabled = *patch.AINegotiationEnabled
+	}
+	if patch.MinPrice.Set {
+		item.MinPrice = patch.MinPrice.Value
+	}
+	if patch.ImageURL != nil {
+		item.ImageURL = *patch.ImageURL
+	}
+	if patch.AutoApproveAnswers != nil {
+		item.AutoApproveAnswers = *patch.AutoApproveAnswers
+	}
+	if patch.AutoApproveMinPrice.Set {
+		item.AutoApproveMinPrice = patch.AutoApproveMinPrice.Value
+	}
+	if patch.CategoryID.Set {
+		item.CategoryID = patch.CategoryID.Value
+	}
+	if patch.Brand != nil {
+		item.Brand = *patch.Brand
+	}
+	if patch.Condition != nil {
+		item.Condition = *patch.Condition
+	}
+	if patch.Attributes != nil {
+		item.Attributes = *patch.Attributes
+	}
+	if patch.ShippingMethod != nil {
+		item.ShippingMethod = *patch.ShippingMethod
+	}
+	if patch.ShippingPayer != nil {
+		item.ShippingPayer = *patch.ShippingPayer
+	}
+	if patch.ShipFrom != nil {
+		item.ShipFrom = *patch.ShipFrom
+	}
+	if patch.DaysToShip != nil {
+		item.DaysToShip = *patch.DaysToShip
+	}
+	if patch.SizeClass != nil {
+		item.SizeClass = *patch.SizeClass
+	}
+	if patch.Price != nil {
+		item.Price = *patch.Price
+	}
+	if patch.ResetInitialPrice || item.Price > item.InitialPrice {
+		item.InitialPrice = item.Price
+	}
 }
 
 // ------ Message / Smart-Nego Logic ------
@@ -163,160 +450,409 @@ type GeminiResponse struct {
 	ResponseContent string `json:"response_content"`
 }
 
-func (u *ItemUsecase) SendMessage(itemID string, senderID string, content string) (*model.Message, *model.Message, error) {
-	// 1. Save User Message
+// shouldAutoApprove reports whether an AI draft falls within the bounds the
+// seller opted in to, so it can be published without manual review.
+// ANSWER drafts follow AutoApproveAnswers; ACCEPT/COUNTER drafts require a
+// suggested price at or above AutoApproveMinPrice (and never below MinPrice)
+// on an item still on sale.
+func shouldAutoApprove(item *model.Item, decision string, suggestedPrice *int) bool {
+	switch strings.ToUpper(decision) {
+	case "ANSWER":
+		return item.AutoApproveAnswers
+	case "ACCEPT", "AGREEMENT", "COUNTER":
+		if item.AutoApproveMinPrice == nil || suggestedPrice == nil || item.Status != model.ItemStatusOnSale {
+			return false
+		}
+		if checkNegotiatedPrice(item, *suggestedPrice) != nil {
+			return false
+		}
+		return *suggestedPrice >= *item.AutoApproveMinPrice
+	default:
+		// REJECT and anything unexpected always waits for the seller
+		return false
+	}
+}
+
+// selectPromptVersion picks the negotiation prompt for an (item, buyer) conversation,
+// honouring a per-item pin before falling back to the experiment buckets.
+func (u *ItemUsecase) selectPromptVersion(item *model.Item, buyerID string) string {
+	override := ""
+	if item.PromptVersion != nil {
+		override = *item.PromptVersion
+	}
+	return u.geminiClient.Prompts().Select(override, item.ID+":"+buyerID)
+}
+
+// draftOutcome is the initial outcome logged for a freshly generated AI message.
+func draftOutcome(aiMsg *model.Message) string {
+	if aiMsg.IsApproved {
+		return model.OutcomeApproved
+	}
+	return model.OutcomePending
+}
+
+// applySuggestedPrice moves the item price to the one carried by an approved
+// message and records the change in the price history. A price the listing
+// could not be saved with (below min_price, out of range), or on an item no
+// longer on sale, is refused.
+func applySuggestedPrice(ctx context.Context, tx *dao.Tx, msg *model.Message) error {
+	if msg.SuggestedPrice == nil || *msg.SuggestedPrice <= 0 {
+		return nil
+	}
+	item, err := tx.Items.GetByIDForUpdate(ctx, msg.ItemID)
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		return notFound("item")
+	}
+	if item.Status != model.ItemStatusOnSale {
+		return conflict("the item is no longer on sale")
+	}
+	if err := checkNegotiatedPrice(item, *msg.SuggestedPrice); err != nil {
+		return err
+	}
+	oldPrice := item.Price
+	item.Price = *msg.SuggestedPrice
+	if err := tx.Items.Update(ctx, item); err != nil {
+		return err
+	}
+	return recordPriceChange(ctx, tx, item, &oldPrice, model.PriceSourceNegotiation, "", &msg.ID)
+}
+
+// checkNegotiatedPrice keeps a negotiated price within what validateItem accepts.
+func checkNegotiatedPrice(item *model.Item, price int) error {
+	if price < minItemPrice || price > maxItemPrice {
+		return conflict(fmt.Sprintf("the negotiated price ¥%d is outside ¥%d to ¥%d", price, minItemPrice, maxItemPrice))
+	}
+	if item.MinPrice != nil && price < *item.MinPrice {
+		return conflict(fmt.Sprintf("the negotiated price ¥%d is below the minimum price ¥%d; lower min_price first", price, *item.MinPrice))
+	}
+	return nil
+}
+
+// resolveThread works out which buyer's thread a negotiation message belongs to.
+// Buyers always write to their own thread; the seller must name the buyer they reply to.
+func resolveThread(item *model.Item, senderID string, buyerID string) (string, error) {
+	if senderID == "" {
+		return "", unauthorized()
+	}
+	if item.UserID == senderID {
+		if buyerID == "" {
+			return "", invalid("buyer_id", "buyer_id required for seller messages")
+		}
+		return buyerID, nil
+	}
+	if buyerID != "" && buyerID != senderID {
+		return "", forbidden("buyers can only access their own thread")
+	}
+	return senderID, nil
+}
+
+// SendMessage saves a user message and returns straight away. If the message
+// needs an AI reply (Smart-Nego or a public answer), a job is queued in the same
+// transaction and returned so the client can follow it; the reply arrives later.
+func (u *ItemUsecase) SendMessage(ctx context.Context, itemID string, senderID string, content string, buyerID string, channel string, replyToID string) (*model.Message, *model.AIJob, error) {
+	ctx, cancel := withDeadline(ctx, deadlines.Write)
+	defer cancel()
+	// 1. Fetch Item Context
+	item, err := u.itemRepo.GetByID(ctx, itemID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if item == nil {
+		return nil, nil, notFound("item")
+	}
+
+	// 2. Resolve channel / thread
+	if channel == "" {
+		channel = model.ChannelNegotiation
+	}
+	switch channel {
+	case model.ChannelPublic:
+		buyerID = "" // Public Q&A is shared by every viewer
+		if senderID == "" {
+			return nil, nil, unauthorized()
+		}
+		if replyToID != "" {
+			if err := u.validateReplyTo(ctx, itemID, replyToID); err != nil {
+				return nil, nil, err
+			}
+		}
+	case model.ChannelNegotiation:
+		buyerID, err = resolveThread(item, senderID, buyerID)
+		if err != nil {
+			return nil, nil, err
+		}
+		replyToID = ""
+	default:
+		return nil, nil, invalid("channel", "unknown channel")
+	}
+
+	// 3. Save User Message
 	userMsgID := ulid.MustNew(ulid.Now(), ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)).String()
 	userMsg := &model.Message{
 		ID:           userMsgID,
 		ItemID:       itemID,
+		BuyerID:      buyerID,
+		Channel:      channel,
+		ReplyToID:    replyToID,
 		SenderID:     senderID,
 		Content:      content,
 		IsAIResponse: false,
         IsApproved:   true, // Human messages are auto-approved
 		CreatedAt:    time.Now(),
 	}
-	if err := u.msgRepo.CreateMessage(userMsg); err != nil {
+	// 4. Queue an AI reply
+	// Only trigger AI if enabled AND sender is NOT the seller (assuming buyer is sending message)
+	var job *model.AIJob
+	if item.AINegotiationEnabled && item.UserID != senderID && u.geminiClient != nil {
+		if channel == model.ChannelPublic {
+			job = newAIJob(model.JobPublicAnswer, userMsg)
+		} else {
+			job = newAIJob(model.JobNegotiationReply, userMsg)
+		}
+	}
+
+	err = u.inTx(ctx, func(tx *dao.Tx) error {
+		if err := tx.Messages.CreateMessage(ctx, userMsg); err != nil {
+			return err
+		}
+		// A seller reply on the public channel goes straight into the FAQ
+		if channel == model.ChannelPublic && senderID == item.UserID {
+			if err := recordFAQ(ctx, tx, userMsg); err != nil {
+				return err
+			}
+		}
+		if job != nil {
+			if err := tx.Jobs.Create(ctx, job); err != nil {
+				return err
+			}
+		}
+		return emitMessageEvent(ctx, tx, model.EventMessageCreated, item, userMsg)
+	})
+	if err != nil {
 		return nil, nil, err
 	}
+	if job != nil {
+		u.jobs.Wake()
+	}
+	return userMsg, job, nil
+}
 
-	// 2. Fetch Item Context
-	item, err := u.itemRepo.GetByID(itemID)
+// GetMessageJob returns the AI job answering a message, for the seller or the message's sender.
+func (u *ItemUsecase) GetMessageJob(ctx context.Context, messageID string, requesterID string) (*model.AIJob, error) {
+	ctx, cancel := withDeadline(ctx, deadlines.Read)
+	defer cancel()
+	msg, err := u.msgRepo.GetMessageByID(ctx, messageID)
 	if err != nil {
-		return userMsg, nil, err
+		return nil, notFound("message")
+	}
+	item, err := u.itemRepo.GetByID(ctx, msg.ItemID)
+	if err != nil {
+		return nil, err
 	}
 	if item == nil {
-		return userMsg, nil, errors.New("item not found")
+		return nil, notFound("item")
+	}
+	if requesterID == "" {
+		return nil, unauthorized()
+	}
+	if requesterID != item.UserID && requesterID != msg.SenderID {
+		return nil, forbidden("only the seller or the sender can see this job")
 	}
+	job, err := u.msgJobs.GetByMessageID(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, notFound("job")
+	}
+	return job, nil
+}
 
-	// 3. Check AI Negotiation
-	fmt.Printf("DEBUG: Checking AI Nego. ItemEnabled=%v, ItemUser=%s, Sender=%s\n", item.AINegotiationEnabled, item.UserID, senderID)
-	
-	// Only trigger AI if enabled AND sender is NOT the seller (assuming buyer is sending message)
-	if item.AINegotiationEnabled && item.UserID != senderID {
-		// Calculate Effective MAP
-		effectiveMAP := int(float64(item.Price) * 0.75) // Default 75%
-		if item.MinPrice != nil {
-			effectiveMAP = *item.MinPrice
-		}
-
-		// Calculate Duration
-		daysListed := int(time.Since(item.CreatedAt).Hours() / 24)
-
-		// Fetch History
-		previousMsgs, err := u.msgRepo.GetMessagesByItemID(itemID)
-		var history []gemini.MessageHistory
-		if err == nil {
-			for _, m := range previousMsgs {
-				role := "Buyer"
-				if m.SenderID == item.UserID {
-					role = "Seller"
-				}
-				history = append(history, gemini.MessageHistory{
-					Sender:  role,
-					Content: m.Content,
-				})
+// ProcessNegotiationJob generates the Smart-Nego draft for a buyer's thread message.
+// It runs on the AI worker pool; returning an error retries the job.
+func (u *ItemUsecase) ProcessNegotiationJob(ctx context.Context, job *model.AIJob) error {
+	item, err := u.itemRepo.GetByID(ctx, job.ItemID)
+	if err != nil {
+		return err
+	}
+	if item == nil || !item.AINegotiationEnabled {
+		return u.finishJob(ctx, job, model.JobSkipped, "AI negotiation not enabled")
+	}
+	if item.Status != model.ItemStatusOnSale {
+		return u.finishJob(ctx, job, model.JobSkipped, "item no longer on sale")
+	}
+
+	// Fetch History (this buyer's thread only): everything before the message we answer
+	previousMsgs, err := u.msgRepo.GetMessagesByThread(ctx, job.ItemID, job.BuyerID)
+	if err != nil {
+		return err
+	}
+	var trigger *model.Message
+	var historyClean []gemini.MessageHistory
+	for i := range previousMsgs {
+		m := previousMsgs[i]
+		if trigger != nil {
+			// The buyer wrote again while this job waited: that message's job answers both
+			if m.SenderID != item.UserID {
+				return u.finishJob(ctx, job, model.JobSkipped, "superseded by a newer buyer message")
 			}
+			continue
 		}
-		// Add current user message to history effectively (the prompt treats it separately as "Current Message", but good conceptually)
-		// Actually prompt separates it. So we pass history EXCLUDING current message if we pulled from DB?
-		// Wait, we just inserted userMsg into DB at step 1.
-		// So GetMessagesByItemID will include the current message.
-		// We should probably filter it out or just rely on the prompt to see it in history?
-		// The prompt has a separate "Current Buyer Message" section.
-		// To avoid duplication, let's exclude the very last message if it matches.
-		// Or simpler: The Prompt says "Conversation History" and "Current Buyer Message".
-		// If the history includes the current message, the AI sees it twice.
-		// Let's filter slightly.
-		// Actually, `userMsg` is separate. `previousMsgs` logic depends on transaction isolation, but usually it might trigger read-your-writes.
-		// Let's assume `previousMsgs` contains it.
-		// Let's pass the raw list but handle the "Current Message" distinctly in prompt.
-		// Refined approach: Don't fetch the just-inserted message if possible, or filter it.
-		// Since we generated `userMsgID` and inserted it, we can filter by ID.
-		var historyClean []gemini.MessageHistory
-		for _, m := range previousMsgs {
-			if m.ID == userMsgID {
-				continue // Skip the message we just sent, as we pass it as 'content'
-			}
-			role := "Buyer"
-			if m.SenderID == item.UserID {
-				role = "Seller"
-			}
-			historyClean = append(historyClean, gemini.MessageHistory{
-				Sender:  role,
-				Content: m.Content,
-			})
-		}
-
-		// Call Vertex AI
-		ctx := context.Background()
-		if u.geminiClient != nil {
-			fmt.Println("DEBUG: Calling Gemini Client...")
-			negotiationResp, err := u.geminiClient.GenerateNegotiationResponse(ctx, item.Price, item.InitialPrice, effectiveMAP, item.ViewsCount, content, daysListed, historyClean, "", "", "", item.Description)
-			if err == nil {
-				fmt.Printf("DEBUG: Gemini Response Received! Decision: %s, Intent: %s\n", negotiationResp.Decision, negotiationResp.Intent)
-				// Create AI Message
-				aiMsgID := ulid.MustNew(ulid.Now(), ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)).String()
-				aiMsg := &model.Message{
-					ID:           aiMsgID,
-					ItemID:       itemID,
-					SenderID:     item.UserID, // Set sender as Seller (AI Agent)
-					Content:      negotiationResp.ResponseContent,
-					IsAIResponse: true, 
-                    IsApproved:   false, // Default unapproved
-					CreatedAt:    time.Now().Add(time.Second), // Ensure timestamp is distinct from UserMsg if same second
-				}
-
-                // Logic to set SuggestedPrice based on Gemini Response
-                decisionLower := strings.ToLower(negotiationResp.Decision)
-                if (decisionLower == "agreement" || decisionLower == "accept") && negotiationResp.DetectedPrice > 0 {
-                    aiMsg.SuggestedPrice = &negotiationResp.DetectedPrice
-                } else if negotiationResp.CounterPrice > 0 {
-                    // It is a COUNTER. Update SuggestedPrice so approval updates the Item Price.
-                    // This allows "Current Price" to track negotiation progress, while InitialPrice stays static.
-                     aiMsg.SuggestedPrice = &negotiationResp.CounterPrice
-                }
-
-				u.msgRepo.CreateMessage(aiMsg)
-
-				// Create Log
-				logID := ulid.MustNew(ulid.Now(), ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)).String()
-                
-				negotiationLog := &model.NegotiationLog{
-					ID:            logID,
-					ItemID:        itemID,
-					UserID:        senderID, // Buyer
-					ProposedPrice: negotiationResp.DetectedPrice,
-					AIDecision:    negotiationResp.Decision,
-                    CounterPrice:  negotiationResp.CounterPrice,
-					AIReasoning:   negotiationResp.Reasoning,
-					LogTime:       time.Now(),
-				}
-				u.msgRepo.CreateNegotiationLog(negotiationLog)
-
-				return userMsg, aiMsg, nil
-			} else {
-				fmt.Println("Gemini Error:", err)
-			}
+		if m.ID == job.MessageID {
+			trigger = &m
+			continue
 		}
+		role := "Buyer"
+		if m.SenderID == item.UserID {
+			role = "Seller"
+		}
+		historyClean = append(historyClean, gemini.MessageHistory{
+			Sender:  role,
+			Content: m.Content,
+		})
+	}
+	if trigger == nil {
+		return u.finishJob(ctx, job, model.JobSkipped, "message no longer exists")
 	}
 
-	return userMsg, nil, nil
+	// Calculate Effective MAP
+	effectiveMAP := int(float64(item.Price) * 0.75) // Default 75%
+	if item.MinPrice != nil {
+		effectiveMAP = *item.MinPrice
+	}
+
+	// Calculate Duration
+	daysListed := int(time.Since(item.CreatedAt).Hours() / 24)
+
+	// Call Vertex AI
+	promptVersion := u.selectPromptVersion(item, trigger.SenderID)
+	llmCtx, cancel := withDeadline(ctx, deadlines.LLM)
+	defer cancel()
+	negotiationResp, err := u.geminiClient.GenerateNegotiationResponse(llmCtx, promptVersion, gemini.NegotiationPromptData{
+		InitialPrice:    item.InitialPrice,
+		CurrentPrice:    item.Price,
+		MinPrice:        effectiveMAP,
+		Views:           item.ViewsCount,
+		DaysListed:      daysListed,
+		ItemDescription: item.Description,
+		Details:         u.promptDetails(ctx, item),
+		Shipping:        promptShipping(item),
+		FAQ:             u.faqEntries(ctx, item.ID),
+		PriceHistory:    u.priceHistoryEntries(ctx, item.ID),
+		History:         historyClean,
+		CurrentMessage:  trigger.Content,
+	})
+	if err != nil {
+		return upstreamAI(err)
+	}
+
+	// Create AI Message
+	aiMsgID := ulid.MustNew(ulid.Now(), ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)).String()
+	aiMsg := &model.Message{
+		ID:           aiMsgID,
+		ItemID:       item.ID,
+		BuyerID:      job.BuyerID,
+		Channel:      model.ChannelNegotiation,
+		SenderID:     item.UserID, // Set sender as Seller (AI Agent)
+		Content:      negotiationResp.ResponseContent,
+		IsAIResponse: true,
+		IsApproved:   false, // Default unapproved
+		CreatedAt:    time.Now(),
+	}
+
+	// Logic to set SuggestedPrice based on Gemini Response
+	decisionLower := strings.ToLower(negotiationResp.Decision)
+	if (decisionLower == "agreement" || decisionLower == "accept") && negotiationResp.DetectedPrice > 0 {
+		aiMsg.SuggestedPrice = &negotiationResp.DetectedPrice
+	} else if negotiationResp.CounterPrice > 0 {
+		// It is a COUNTER. Update SuggestedPrice so approval updates the Item Price.
+		// This allows "Current Price" to track negotiation progress, while InitialPrice stays static.
+		aiMsg.SuggestedPrice = &negotiationResp.CounterPrice
+	}
+
+	// Publish immediately if the seller opted in to auto-approval for this kind of draft
+	aiMsg.IsApproved = shouldAutoApprove(item, negotiationResp.Decision, aiMsg.SuggestedPrice)
+
+	// Create Log
+	logID := ulid.MustNew(ulid.Now(), ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)).String()
+	negotiationLog := &model.NegotiationLog{
+		ID:            logID,
+		ItemID:        item.ID,
+		UserID:        trigger.SenderID, // Buyer
+		MessageID:     aiMsg.ID,
+		ProposedPrice: negotiationResp.DetectedPrice,
+		AIDecision:    negotiationResp.Decision,
+		CounterPrice:  negotiationResp.CounterPrice,
+		AIReasoning:   negotiationResp.Reasoning,
+		AutoApproved:  aiMsg.IsApproved,
+		PromptVersion: negotiationResp.PromptVersion,
+		Outcome:       draftOutcome(aiMsg),
+		LogTime:       time.Now(),
+	}
+
+	// Save the draft, its price effect, its log, its events and the job result together
+	return u.inTx(ctx, func(tx *dao.Tx) error {
+		if err := tx.Messages.CreateMessage(ctx, aiMsg); err != nil {
+			return err
+		}
+		if aiMsg.IsApproved {
+			if err := applySuggestedPrice(ctx, tx, aiMsg); err != nil {
+				return err
+			}
+		}
+		if err := tx.Messages.CreateNegotiationLog(ctx, negotiationLog); err != nil {
+			return err
+		}
+		if err := emitDraftEvents(ctx, tx, item, aiMsg); err != nil {
+			return err
+		}
+		return tx.Jobs.Finish(ctx, job.ID, model.JobSucceeded, aiMsg.ID, "")
+	})
 }
 
-func (u *ItemUsecase) GetMessages(itemID string, requesterID string) ([]model.Message, error) {
-    // Fetch all messages for the item
-    allMsgs, err := u.msgRepo.GetMessagesByItemID(itemID)
-    if err != nil {
-        return nil, err
-    }
-    
+// finishJob ends a job that has nothing to produce.
+func (u *ItemUsecase) finishJob(ctx context.Context, job *model.AIJob, status string, note string) error {
+	return u.txm.WithTx(ctx, func(tx *dao.Tx) error {
+		return tx.Jobs.Finish(ctx, job.ID, status, "", note)
+	})
+}
+
+// GetMessages returns one conversation of an item.
+// Negotiation threads are visible only to their participants: the seller (who
+// picks the thread with buyerID) and that buyer. The public channel is open to all.
+func (u *ItemUsecase) GetMessages(ctx context.Context, itemID string, requesterID string, buyerID string, channel string) ([]model.Message, error) {
+    ctx, cancel := withDeadline(ctx, deadlines.Read)
+    defer cancel()
     // Check ownership to decide visibility
-    item, err := u.itemRepo.GetByID(itemID)
+    item, err := u.itemRepo.GetByID(ctx, itemID)
     if err != nil {
         return nil, err
     }
     if item == nil {
-        return nil, errors.New("item not found")
+        return nil, notFound("item")
+    }
+
+    var allMsgs []model.Message
+    switch channel {
+    case model.ChannelPublic:
+        allMsgs, err = u.msgRepo.GetMessagesByChannel(ctx, itemID, model.ChannelPublic)
+    case "", model.ChannelNegotiation:
+        if requesterID == "" {
+            return nil, unauthorized()
+        }
+        buyerID, err = resolveThread(item, requesterID, buyerID)
+        if err != nil {
+            return nil, err
+        }
+        allMsgs, err = u.msgRepo.GetMessagesByThread(ctx, itemID, buyerID)
+    default:
+        return nil, invalid("channel", "unknown channel")
+    }
+    if err != nil {
+        return nil, err
     }
 
     // If requester is seller, return all (and they include reasons due to repo join logic)
@@ -324,58 +860,171 @@ func (u *ItemUsecase) GetMessages(itemID string, requesterID string) ([]model.Me
         return allMsgs, nil
     }
 
-    // If requester is buyer (or anonymous), filter unapproved AI messages
+    // If requester is the buyer (or anyone, on the public channel), filter unapproved AI messages
     var filteredMsgs []model.Message
     for _, msg := range allMsgs {
         if msg.IsApproved {
-            // Hide reasoning for non-sellers
-            msg.AIReasoning = "" 
+            // Hide reasoning and negotiation details for non-sellers
+            msg.AIReasoning = ""
+            msg.AIDecision = ""
+            msg.DetectedPrice = nil
+            msg.CounterPrice = nil
             filteredMsgs = append(filteredMsgs, msg)
         }
     }
 	return filteredMsgs, nil
 }
 
-func (u *ItemUsecase) ApproveMessage(messageID string) error {
-    msg, err := u.msgRepo.GetMessageByID(messageID)
+// ApproveMessage publishes a draft. Approving an offer moves the item price,
+// so only the seller may, and only while the item is on sale.
+func (u *ItemUsecase) ApproveMessage(ctx context.Context, messageID string, userID string) error {
+    if userID == "" {
+        return unauthorized()
+    }
+    return u.inTx(ctx, func(tx *dao.Tx) error {
+        msg, err := tx.Messages.GetMessageByID(ctx, messageID)
+        if errors.Is(err, sql.ErrNoRows) {
+            return notFound("message")
+        }
+        if err != nil {
+            return err
+        }
+        // Locked so the status and price checks hold until commit
+        item, err := tx.Items.GetByIDForUpdate(ctx, msg.ItemID)
+        if err != nil {
+            return err
+        }
+        if item == nil || item.Status == "deleted" {
+            return notFound("item")
+        }
+        if item.UserID != userID {
+            return forbidden("only the seller can approve messages")
+        }
+        // Approving again must not undo a price the seller has set since
+        if msg.IsApproved {
+            return nil
+        }
+        if item.Status != "on_sale" {
+            return conflict("item is not on sale")
+        }
+
+        // Auto-Update Price if SuggestedPrice exists
+        if err := applySuggestedPrice(ctx, tx, msg); err != nil {
+            return err
+        }
+
+        if err := tx.Messages.ApproveMessage(ctx, messageID); err != nil {
+            return err
+        }
+        if err := tx.Messages.SetNegotiationOutcome(ctx, messageID, model.OutcomeApproved); err != nil {
+            return err
+        }
+
+        // Approved public answers become part of the item FAQ
+        if err := recordFAQ(ctx, tx, msg); err != nil {
+            return err
+        }
+        msg.IsApproved = true
+        return emitMessageEvent(ctx, tx, model.EventDraftApproved, item, msg)
+    })
+}
+
+// GetThreads lists an item's negotiation threads: all of them for the seller (inbox),
+// only the requester's own thread for anyone else.
+func (u *ItemUsecase) GetThreads(ctx context.Context, itemID string, requesterID string) ([]model.Thread, error) {
+    ctx, cancel := withDeadline(ctx, deadlines.Read)
+    defer cancel()
+    item, err := u.itemRepo.GetByID(ctx, itemID)
+    if err != nil {
+        return nil, err
+    }
+    if item == nil {
+        return nil, notFound("item")
+    }
+    if requesterID == "" {
+        return nil, unauthorized()
+    }
+
+    // The query only returns threads the requester takes part in
+    return u.msgRepo.GetThreads(ctx, requesterID, itemID)
+}
+
+// GetInbox lists every negotiation thread the user takes part in, as seller or buyer,
+// with unread and pending-draft counts.
+func (u *ItemUsecase) GetInbox(ctx context.Context, userID string) ([]model.Thread, error) {
+    ctx, cancel := withDeadline(ctx, deadlines.Read)
+    defer cancel()
+    if userID == "" {
+        return nil, unauthorized()
+    }
+    return u.msgRepo.GetThreads(ctx, userID, "")
+}
+
+// MarkThreadRead records that the user has seen everything currently visible to
+// them in a negotiation thread.
+func (u *ItemUsecase) MarkThreadRead(ctx context.Context, itemID string, userID string, buyerID string) error {
+    ctx, cancel := withDeadline(ctx, deadlines.Write)
+    defer cancel()
+    item, err := u.itemRepo.GetByID(ctx, itemID)
     if err != nil {
         return err
     }
-    
-    // Auto-Update Price if SuggestedPrice exists
-    if msg.SuggestedPrice != nil && *msg.SuggestedPrice > 0 {
-        item, err := u.itemRepo.GetByID(msg.ItemID)
-        if err == nil && item != nil {
-            item.Price = *msg.SuggestedPrice
-            _ = u.itemRepo.Update(item) // Ignore error? Or fail? Better to fail if price update fails.
-            // But we want to approve anyway? 
-            // Let's assume critical: if price update fails, don't approve.
-        }
+    if item == nil {
+        return notFound("item")
+    }
+    buyerID, err = resolveThread(item, userID, buyerID)
+    if err != nil {
+        return err
     }
 
-    return u.msgRepo.ApproveMessage(messageID)
+    msgs, err := u.msgRepo.GetMessagesByThread(ctx, itemID, buyerID)
+    if err != nil {
+        return err
+    }
+    lastVisibleID := ""
+    for _, m := range msgs {
+        if m.IsApproved || item.UserID == userID {
+            lastVisibleID = m.ID
+        }
+    }
+    if lastVisibleID == "" {
+        return nil
+    }
+    return u.msgRepo.MarkThreadRead(ctx, itemID, buyerID, userID, lastVisibleID)
 }
 
-func (u *ItemUsecase) RegenerateAIMessage(itemID string, userID string, instruction string) (*model.Message, error) {
+func (u *ItemUsecase) RegenerateAIMessage(ctx context.Context, itemID string, userID string, buyerID string, instruction string) (*model.Message, error) {
     // 1. Verify Ownership / Authorization
-    item, err := u.itemRepo.GetByID(itemID)
+    item, err := u.itemRepo.GetByID(ctx, itemID)
     if err != nil {
         return nil, err
     }
     if item == nil {
-        return nil, errors.New("item not found")
+        return nil, notFound("item")
     }
     if item.UserID != userID {
-        return nil, errors.New("unauthorized: only seller can regenerate AI response")
+        return nil, forbidden("only the seller can regenerate AI responses")
     }
     if !item.AINegotiationEnabled {
-        return nil, errors.New("AI negotiation not enabled")
+        return nil, conflict("AI negotiation not enabled")
+    }
+
+    // Older clients don't name the thread: fall back to the most recently active one
+    if buyerID == "" {
+        threads, err := u.msgRepo.GetThreads(ctx, userID, itemID)
+        if err != nil {
+            return nil, err
+        }
+        if len(threads) == 0 {
+            return nil, conflict("no buyer message found to respond to")
+        }
+        buyerID = threads[0].BuyerID
     }
 
     // 2. Find Context (Last Buyer Message)
     // We need to find the message the AI *should* be responding to.
     // This is typically the last message from a Buyer (SenderID != Owner).
-    allMsgs, err := u.msgRepo.GetMessagesByItemID(itemID)
+    allMsgs, err := u.msgRepo.GetMessagesByThread(ctx, itemID, buyerID)
     if err != nil {
         return nil, err
     }
@@ -409,7 +1058,7 @@ func (u *ItemUsecase) RegenerateAIMessage(itemID string, userID string, instruct
     }
 
     if lastBuyerMsg == nil {
-        return nil, errors.New("no buyer message found to respond to")
+        return nil, conflict("no buyer message found to respond to")
     }
 
     // Remove the lastAIUnapprovedMsg from history if we added it (it shouldn't be part of history for regeneration)
@@ -458,12 +1107,8 @@ func (u *ItemUsecase) RegenerateAIMessage(itemID string, userID string, instruct
         }
     }
 
-    // 3. Delete Old AI Draft (Clean up)
-    if lastAIUnapprovedMsgID != "" {
-        _ = u.msgRepo.DeleteMessage(lastAIUnapprovedMsgID)
-    }
-
-    // 3.5 Extract Previous Draft Content & Reasoning for Retry Context
+    // 3. Extract Previous Draft Content & Reasoning for Retry Context
+    // (the old draft itself is replaced in step 5, once the new one exists)
     var prevContent, prevReasoning string
     if lastAIUnapprovedMsgID != "" {
         // We need to fetch the full message object before deletion, or just assume we have it if we stored it?
@@ -485,11 +1130,29 @@ func (u *ItemUsecase) RegenerateAIMessage(itemID string, userID string, instruct
     }
     daysListed := int(time.Since(item.CreatedAt).Hours() / 24)
 
-    ctx := context.Background()
     // Retry instruction injected here along with previous draft context
-    negotiationResp, err := u.geminiClient.GenerateNegotiationResponse(ctx, item.Price, item.InitialPrice, effectiveMAP, item.ViewsCount, lastBuyerMsg.Content, daysListed, historyClean, instruction, prevContent, prevReasoning, item.Description)
+    promptVersion := u.selectPromptVersion(item, lastBuyerMsg.SenderID)
+    llmCtx, cancel := withDeadline(ctx, deadlines.LLM)
+    defer cancel()
+    negotiationResp, err := u.geminiClient.GenerateNegotiationResponse(llmCtx, promptVersion, gemini.NegotiationPromptData{
+        InitialPrice:           item.InitialPrice,
+        CurrentPrice:           item.Price,
+        MinPrice:               effectiveMAP,
+        Views:                  item.ViewsCount,
+        DaysListed:             daysListed,
+        ItemDescription:        item.Description,
+        Details:                u.promptDetails(ctx, item),
+        Shipping:               promptShipping(item),
+        FAQ:                    u.faqEntries(ctx, itemID),
+        PriceHistory:           u.priceHistoryEntries(ctx, itemID),
+        History:                historyClean,
+        CurrentMessage:         lastBuyerMsg.Content,
+        RetryInstruction:       instruction,
+        PreviousDraftContent:   prevContent,
+        PreviousDraftReasoning: prevReasoning,
+    })
     if err != nil {
-        return nil, err
+        return nil, upstreamAI(err)
     }
 
     // 5. Save New AI Message
@@ -497,11 +1160,13 @@ func (u *ItemUsecase) RegenerateAIMessage(itemID string, userID string, instruct
     aiMsg := &model.Message{
         ID:           aiMsgID,
         ItemID:       itemID,
+        BuyerID:      buyerID,
+        Channel:      model.ChannelNegotiation,
         SenderID:     item.UserID,
         Content:      negotiationResp.ResponseContent,
         IsAIResponse: true,
         IsApproved:   false,
-        CreatedAt:    time.Now().Add(time.Second), // Ensure distinctive timestamp
+        CreatedAt:    time.Now(),
     }
     
     // Price logic 
@@ -511,10 +1176,7 @@ func (u *ItemUsecase) RegenerateAIMessage(itemID string, userID string, instruct
     } else if negotiationResp.CounterPrice > 0 {
          aiMsg.SuggestedPrice = &negotiationResp.CounterPrice
     }
-
-    if err := u.msgRepo.CreateMessage(aiMsg); err != nil {
-        return nil, err
-    }
+    aiMsg.IsApproved = shouldAutoApprove(item, negotiationResp.Decision, aiMsg.SuggestedPrice)
 
     // Log (Append "RETRY" to decision or reasoning to track it?)
     logID := ulid.MustNew(ulid.Now(), ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)).String()
@@ -522,21 +1184,142 @@ func (u *ItemUsecase) RegenerateAIMessage(itemID string, userID string, instruct
         ID:            logID,
         ItemID:        itemID,
         UserID:        lastBuyerMsg.SenderID,
+        MessageID:     aiMsg.ID,
         ProposedPrice: negotiationResp.DetectedPrice,
         AIDecision:    negotiationResp.Decision + " (RETRY)",
         CounterPrice:  negotiationResp.CounterPrice,
         AIReasoning:   negotiationResp.Reasoning,
+        AutoApproved:  aiMsg.IsApproved,
+        PromptVersion: negotiationResp.PromptVersion,
+        Outcome:       draftOutcome(aiMsg),
         LogTime:       time.Now(),
     }
-    u.msgRepo.CreateNegotiationLog(negotiationLog)
+
+    // Swap the old draft for the new one in a single transaction
+    err = u.inTx(ctx, func(tx *dao.Tx) error {
+        if lastAIUnapprovedMsgID != "" {
+            if err := tx.Messages.SetNegotiationOutcome(ctx, lastAIUnapprovedMsgID, model.OutcomeRegenerated); err != nil {
+                return err
+            }
+            if err := tx.Messages.DeleteMessage(ctx, lastAIUnapprovedMsgID); err != nil {
+                return err
+            }
+        }
+        if err := tx.Messages.CreateMessage(ctx, aiMsg); err != nil {
+            return err
+        }
+        if aiMsg.IsApproved {
+            if err := applySuggestedPrice(ctx, tx, aiMsg); err != nil {
+                return err
+            }
+        }
+        if err := tx.Messages.CreateNegotiationLog(ctx, negotiationLog); err != nil {
+            return err
+        }
+        return emitDraftEvents(ctx, tx, item, aiMsg)
+    })
+    if err != nil {
+        return nil, err
+    }
 
     return aiMsg, nil
 }
 
-func (u *ItemUsecase) RejectMessage(messageID string, userID string) error {
-    // Ideally verify ownership here too.
-    // For MVP, trust the controller/caller or assuming ID match is sufficient safety for a hackathon.
-    // Logic: Delete the message (draft).
-    return u.msgRepo.DeleteMessage(messageID)
+// RevokeMessage lets the seller override an approved (typically auto-approved)
+// AI message by returning it to draft, hiding it from the buyer again. A price
+// the message set goes back to what it was, unless the price has moved since.
+func (u *ItemUsecase) RevokeMessage(ctx context.Context, messageID string, userID string) error {
+    ctx, cancel := withDeadline(ctx, deadlines.Write)
+    defer cancel()
+    msg, err := u.msgRepo.GetMessageByID(ctx, messageID)
+    if errors.Is(err, sql.ErrNoRows) {
+        return notFound("message")
+    }
+    if err != nil {
+        return err
+    }
+    item, err := u.itemRepo.GetByID(ctx, msg.ItemID)
+    if err != nil {
+        return err
+    }
+    if item == nil {
+        return notFound("item")
+    }
+    if item.UserID != userID {
+        return forbidden("only the seller can revoke messages")
+    }
+    if !msg.IsAIResponse {
+        return conflict("only AI messages can be revoked")
+    }
+    if !msg.IsApproved {
+        return conflict("message is not approved")
+    }
+    return u.inTx(ctx, func(tx *dao.Tx) error {
+        if err := tx.Messages.UnapproveMessage(ctx, messageID); err != nil {
+            return err
+        }
+        if err := tx.FAQs.DeleteByAnswerMessageID(ctx, messageID); err != nil {
+            return err
+        }
+        if err := restoreNegotiatedPrice(ctx, tx, item.ID, messageID, userID); err != nil {
+            return err
+        }
+        return tx.Messages.SetNegotiationOutcome(ctx, messageID, model.OutcomePending)
+    })
+}
+
+// restoreNegotiatedPrice undoes the price change a revoked message made, as a
+// new history entry and item.price_changed event. A sold item keeps its price.
+func restoreNegotiatedPrice(ctx context.Context, tx *dao.Tx, itemID string, messageID string, userID string) error {
+    change, err := tx.Prices.GetNegotiatedByMessageID(ctx, messageID)
+    if err != nil || change == nil || change.OldPrice == nil {
+        return err
+    }
+    item, err := tx.Items.GetByIDForUpdate(ctx, itemID)
+    if err != nil || item == nil {
+        return err
+    }
+    if item.Status != "on_sale" || item.Price != change.NewPrice {
+        return nil
+    }
+    oldPrice := item.Price
+    item.Price = *change.OldPrice
+    if err := tx.Items.Update(ctx, item); err != nil {
+        return err
+    }
+    return recordPriceChange(ctx, tx, item, &oldPrice, model.PriceSourceRevoke, userID, &messageID)
+}
+
+// RejectMessage lets the seller discard a pending AI draft. The draft is
+// deleted; its log keeps the rejection for the stats.
+func (u *ItemUsecase) RejectMessage(ctx context.Context, messageID string, userID string) error {
+    ctx, cancel := withDeadline(ctx, deadlines.Write)
+    defer cancel()
+    msg, err := u.msgRepo.GetMessageByID(ctx, messageID)
+    if errors.Is(err, sql.ErrNoRows) {
+        return notFound("message")
+    }
+    if err != nil {
+        return err
+    }
+    item, err := u.itemRepo.GetByID(ctx, msg.ItemID)
+    if err != nil {
+        return err
+    }
+    if item == nil {
+        return notFound("item")
+    }
+    if item.UserID != userID {
+        return forbidden("only the seller can reject drafts")
+    }
+    if !msg.IsAIResponse || msg.IsApproved {
+        return conflict("only unapproved AI drafts can be rejected")
+    }
+    return u.inTx(ctx, func(tx *dao.Tx) error {
+        if err := tx.Messages.SetNegotiationOutcome(ctx, messageID, model.OutcomeRejected); err != nil {
+            return err
+        }
+        return tx.Messages.DeleteMessage(ctx, messageID)
+    })
 }
 
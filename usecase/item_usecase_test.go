@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"hackathon-backend/model"
+	"testing"
+)
+
+func TestShouldAutoApprove(t *testing.T) {
+	onSale := func() *model.Item {
+		return &model.Item{Price: 1000, Status: model.ItemStatusOnSale, MinPrice: intPtr(600), AutoApproveMinPrice: intPtr(800)}
+	}
+	sold := onSale()
+	sold.Status = model.ItemStatusSold
+	cancelled := onSale()
+	cancelled.Status = model.ItemStatusCancelled
+	answers := onSale()
+	answers.AutoApproveAnswers = true
+
+	tests := []struct {
+		name     string
+		item     *model.Item
+		decision string
+		price    *int
+		want     bool
+	}{
+		{name: "accept at the threshold", item: onSale(), decision: "ACCEPT", price: intPtr(800), want: true},
+		{name: "counter above the threshold", item: onSale(), decision: "counter", price: intPtr(900), want: true},
+		{name: "below the threshold", item: onSale(), decision: "ACCEPT", price: intPtr(799)},
+		{name: "no price", item: onSale(), decision: "ACCEPT"},
+		{name: "reject", item: onSale(), decision: "REJECT", price: intPtr(900)},
+		{name: "sold item", item: sold, decision: "ACCEPT", price: intPtr(900)},
+		{name: "cancelled item", item: cancelled, decision: "COUNTER", price: intPtr(900)},
+		{name: "answers off", item: onSale(), decision: "ANSWER"},
+		{name: "answers on", item: answers, decision: "ANSWER", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shouldAutoApprove(tt.item, tt.decision, tt.price); got != tt.want {
+				t.Errorf("shouldAutoApprove = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"fmt"
+	"hackathon-backend/model"
+	"hackathon-backend/pkg/shipping"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Listing limits. Name matches items.name VARCHAR(100); the rest keep rows and
+// payloads to a sensible size.
+const (
+	maxItemNameLength        = 100
+	maxItemDescriptionLength = 3000
+	minItemPrice             = 1
+	maxItemPrice             = 9_999_999
+	maxImageURLLength        = 2048
+	maxImageBytes            = 5 << 20 // Decoded size of an inline data: image
+	maxBrandLength           = 100     // items.brand VARCHAR(100)
+	maxAttributeValueLength  = 200
+)
+
+// imageTypes are the formats accepted for inline (data:) images.
+var imageTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+
+// validateItem checks what a seller may set on a listing and reports every bad
+// field at once. categories is needed to check the category and its attributes.
+func validateItem(item *model.Item, categories *categoryIndex) error {
+	var fields []FieldError
+	add := func(field string, format string, args ...interface{}) {
+		fields = append(fields, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	item.Name = strings.TrimSpace(item.Name)
+	switch n := utf8.RuneCountInString(item.Name); {
+	case n == 0:
+		add("name", "name is required")
+	case n > maxItemNameLength:
+		add("name", "name must be at most %d characters", maxItemNameLength)
+	}
+	if utf8.RuneCountInString(item.Description) > maxItemDescriptionLength {
+		add("description", "description must be at most %d characters", maxItemDescriptionLength)
+	}
+
+	if item.Price < minItemPrice || item.Price > maxItemPrice {
+		add("price", "price must be between %d and %d", minItemPrice, maxItemPrice)
+	}
+	if item.MinPrice != nil {
+		if *item.MinPrice < minItemPrice {
+			add("min_price", "min_price must be at least %d", minItemPrice)
+		} else if *item.MinPrice > item.Price {
+			add("min_price", "min_price must not exceed price")
+		}
+	}
+	if item.AutoApproveMinPrice != nil {
+		switch {
+		case *item.AutoApproveMinPrice > item.Price:
+			add("auto_approve_min_price", "auto_approve_min_price must not exceed price")
+		case item.MinPrice != nil && *item.AutoApproveMinPrice < *item.MinPrice:
+			add("auto_approve_min_price", "auto_approve_min_price must not be below min_price")
+		case *item.AutoApproveMinPrice < minItemPrice:
+			add("auto_approve_min_price", "auto_approve_min_price must be at least %d", minItemPrice)
+		}
+	}
+
+	if item.ImageURL != "" {
+		if msg := checkImage(item.ImageURL); msg != "" {
+			add("image_url", "%s", msg)
+		}
+	}
+
+	fields = append(fields, validateItemDetails(&item.ItemDetails, categories)...)
+	fields = append(fields, validateShipping(&item.ShippingOptions)...)
+
+	if len(fields) > 0 {
+		return &Error{Kind: ErrValidation, Message: "invalid item", Fields: fields}
+	}
+	return nil
+}
+
+// validateItemDetails checks brand, condition, and that the category is a leaf
+// of the tree and the attributes follow its schema.
+func validateItemDetails(d *model.ItemDetails, categories *categoryIndex) []FieldError {
+	var fields []FieldError
+	add := func(field string, format string, args ...interface{}) {
+		fields = append(fields, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	d.Brand = strings.TrimSpace(d.Brand)
+	if utf8.RuneCountInString(d.Brand) > maxBrandLength {
+		add("brand", "brand must be at most %d characters", maxBrandLength)
+	}
+	if d.Condition != "" && !contains(model.ItemConditions, d.Condition) {
+		add("condition", "condition must be one of %s", strings.Join(model.ItemConditions, ", "))
+	}
+
+	if d.CategoryID == nil {
+		if len(d.Attributes) > 0 {
+			add("attributes", "attributes need a category")
+		}
+		return fields
+	}
+	category, ok := categories.byID[*d.CategoryID]
+	if !ok {
+		add("category_id", "unknown category")
+		return fields
+	}
+	if len(category.Children) > 0 {
+		add("category_id", "choose a subcategory of %s", category.Name)
+	}
+
+	specs := categories.attributes(category.ID)
+	for key := range d.Attributes {
+		if !slices.ContainsFunc(specs, func(spec model.AttributeSpec) bool { return spec.Key == key }) {
+			add("attributes."+key, "%s has no attribute %s", category.Name, key)
+		}
+	}
+	for _, spec := range specs {
+		field := "attributes." + spec.Key
+		value, ok := d.Attributes[spec.Key]
+		value = strings.TrimSpace(value)
+		if !ok || value == "" {
+			delete(d.Attributes, spec.Key)
+			if spec.Required {
+				add(field, "%s is required", spec.Label)
+			}
+			continue
+		}
+		d.Attributes[spec.Key] = value
+		switch {
+		case utf8.RuneCountInString(value) > maxAttributeValueLength:
+			add(field, "%s must be at most %d characters", spec.Label, maxAttributeValueLength)
+		case spec.Type == model.AttributeNumber:
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				add(field, "%s must be a number", spec.Label)
+			}
+		case spec.Type == model.AttributeSelect:
+			if !contains(spec.Options, value) {
+				add(field, "%s must be one of %s", spec.Label, strings.Join(spec.Options, ", "))
+			}
+		}
+	}
+	return fields
+}
+
+// validateShipping checks the shipping options: none at all, or every one set
+// to a value pkg/shipping knows.
+func validateShipping(o *model.ShippingOptions) []FieldError {
+	if *o == (model.ShippingOptions{}) {
+		return nil
+	}
+	var fields []FieldError
+	check := func(field string, value string, allowed []string) {
+		switch {
+		case value == "":
+			fields = append(fields, FieldError{Field: field, Message: field + " is required with shipping options"})
+		case !contains(allowed, value):
+			fields = append(fields, FieldError{Field: field, Message: field + " must be one of " + strings.Join(allowed, ", ")})
+		}
+	}
+	check("shipping_method", o.ShippingMethod, shipping.Methods)
+	check("shipping_payer", o.ShippingPayer, shipping.Payers)
+	check("days_to_ship", o.DaysToShip, shipping.DaysToShip)
+	check("size_class", o.SizeClass, shipping.Sizes)
+	if o.ShipFrom == "" || !shipping.IsPrefecture(o.ShipFrom) {
+		fields = append(fields, FieldError{Field: "ship_from", Message: "ship_from must be a prefecture code from 01 to 47"})
+	}
+	if len(fields) == 0 && !shipping.Supports(o.ShippingMethod, o.SizeClass) {
+		fields = append(fields, FieldError{Field: "size_class", Message: fmt.Sprintf("%s does not take size %s", o.ShippingMethod, o.SizeClass)})
+	}
+	return fields
+}
+
+// checkImage accepts an http(s) URL or a base64 data: URL holding a JPEG, PNG,
+// GIF or WebP image. It returns what is wrong, or "".
+func checkImage(image string) string {
+	if !strings.HasPrefix(image, "data:") {
+		if len(image) > maxImageURLLength {
+			return fmt.Sprintf("image_url must be at most %d characters", maxImageURLLength)
+		}
+		u, err := url.Parse(image)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return "image_url must be an http(s) URL or a data: image"
+		}
+		return ""
+	}
+
+	// data:image/png;base64,....
+	meta, payload, ok := strings.Cut(strings.TrimPrefix(image, "data:"), ",")
+	mediaType, isBase64 := strings.CutSuffix(meta, ";base64")
+	if !ok || !isBase64 || !contains(imageTypes, mediaType) {
+		return "image must be a base64 JPEG, PNG, GIF or WebP"
+	}
+	if base64.StdEncoding.DecodedLen(len(payload)) > maxImageBytes+3 {
+		return fmt.Sprintf("image must be at most %d MB", maxImageBytes>>20)
+	}
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return "image is not valid base64"
+	}
+	if len(data) > maxImageBytes {
+		return fmt.Sprintf("image must be at most %d MB", maxImageBytes>>20)
+	}
+	if sniffed := http.DetectContentType(data); sniffed != mediaType {
+		return fmt.Sprintf("image content is %s, not %s", sniffed, mediaType)
+	}
+	return ""
+}
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"errors"
+	"hackathon-backend/model"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func intPtr(v int) *int { return &v }
+
+// fieldsOf returns the fields a validation error names, or nil for no error.
+func fieldsOf(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if !errors.As(err, &e) || e.Kind != ErrValidation {
+		t.Fatalf("error %v is not a validation error", err)
+	}
+	var fields []string
+	for _, f := range e.Fields {
+		fields = append(fields, f.Field)
+	}
+	return fields
+}
+
+func TestValidateItemPrices(t *testing.T) {
+	tests := []struct {
+		name        string
+		price       int
+		minPrice    *int
+		autoApprove *int
+		want        []string
+	}{
+		{name: "price only", price: 1000},
+		{name: "price too low", price: 0, want: []string{"price"}},
+		{name: "price too high", price: maxItemPrice + 1, want: []string{"price"}},
+		{name: "all set", price: 1000, minPrice: intPtr(500), autoApprove: intPtr(800)},
+		{name: "min_price equals price", price: 1000, minPrice: intPtr(1000), autoApprove: intPtr(1000)},
+		{name: "min_price above price", price: 1000, minPrice: intPtr(1001), want: []string{"min_price"}},
+		{name: "min_price zero", price: 1000, minPrice: intPtr(0), want: []string{"min_price"}},
+		{name: "auto approve above price", price: 1000, autoApprove: intPtr(1200), want: []string{"auto_approve_min_price"}},
+		{name: "auto approve below min_price", price: 1000, minPrice: intPtr(600), autoApprove: intPtr(500), want: []string{"auto_approve_min_price"}},
+		{name: "auto approve zero without min_price", price: 1000, autoApprove: intPtr(0), want: []string{"auto_approve_min_price"}},
+		// Both wrong: min_price is reported and auto approve is checked against it, not against price
+		{name: "min_price and auto approve above price", price: 1000, minPrice: intPtr(1500), autoApprove: intPtr(1200), want: []string{"min_price", "auto_approve_min_price"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := &model.Item{Name: "Camera", Price: tt.price, MinPrice: tt.minPrice, AutoApproveMinPrice: tt.autoApprove}
+			got := fieldsOf(t, validateItem(item, &categoryIndex{}))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateItemTrimsName(t *testing.T) {
+	item := &model.Item{Name: "  Camera  ", Price: 1000}
+	if err := validateItem(item, &categoryIndex{}); err != nil {
+		t.Fatal(err)
+	}
+	if item.Name != "Camera" {
+		t.Errorf("name = %q", item.Name)
+	}
+
+	blank := &model.Item{Name: "   ", Price: 1000}
+	if got := fieldsOf(t, validateItem(blank, &categoryIndex{})); !slices.Equal(got, []string{"name"}) {
+		t.Errorf("fields = %v, want [name]", got)
+	}
+}
+
+func dataURL(mediaType string, data []byte) string {
+	return "data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
+
+func TestCheckImage(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	jpeg := []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00")
+
+	tests := []struct {
+		name  string
+		image string
+		want  string // Substring of the message, "" when accepted
+	}{
+		{name: "https url", image: "https://example.com/a.jpg"},
+		{name: "http url", image: "http://example.com/a.jpg"},
+		{name: "other scheme", image: "ftp://example.com/a.jpg", want: "http(s) URL"},
+		{name: "no host", image: "https:///a.jpg", want: "http(s) URL"},
+		{name: "url too long", image: "https://example.com/" + strings.Repeat("a", maxImageURLLength), want: "at most"},
+		{name: "png", image: dataURL("image/png", png)},
+		{name: "jpeg", image: dataURL("image/jpeg", jpeg)},
+		{name: "declared png holding jpeg", image: dataURL("image/png", jpeg), want: "image content is image/jpeg"},
+		{name: "declared jpeg holding text", image: dataURL("image/jpeg", []byte("<html><script>")), want: "not image/jpeg"},
+		{name: "unsupported type", image: dataURL("image/svg+xml", []byte("<svg/>")), want: "base64 JPEG"},
+		{name: "not base64 encoded", image: "data:image/png," + string(png), want: "base64 JPEG"},
+		{name: "no comma", image: "data:image/png;base64", want: "base64 JPEG"},
+		{name: "bad base64", image: "data:image/png;base64,!!!!", want: "not valid base64"},
+		{name: "just over the limit", image: dataURL("image/png", append(png, make([]byte, maxImageBytes)...)), want: "at most 5 MB"},
+		// Rejected from the encoded length alone, before decoding
+		{name: "far over the limit", image: "data:image/png;base64," + strings.Repeat("A", 2*maxImageBytes), want: "at most 5 MB"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkImage(tt.image)
+			if tt.want == "" && got != "" {
+				t.Errorf("rejected: %s", got)
+			}
+			if tt.want != "" && !strings.Contains(got, tt.want) {
+				t.Errorf("message = %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateItemAttributes(t *testing.T) {
+	parentID := "phones"
+	categories := &categoryIndex{byID: map[string]*model.Category{}}
+	parent := &model.Category{ID: parentID, Name: "Phones", Attributes: []model.AttributeSpec{
+		{Key: "storage", Label: "Storage", Type: model.AttributeNumber},
+	}}
+	leaf := &model.Category{ID: "smartphones", ParentID: &parentID, Name: "Smartphones", Attributes: []model.AttributeSpec{
+		{Key: "carrier", Label: "Carrier", Type: model.AttributeSelect, Options: []string{"docomo", "au", "softbank"}, Required: true},
+	}}
+	parent.Children = []*model.Category{leaf}
+	categories.byID[parent.ID], categories.byID[leaf.ID] = parent, leaf
+
+	tests := []struct {
+		name       string
+		categoryID string
+		attributes map[string]string
+		want       []string
+	}{
+		{name: "valid", categoryID: "smartphones", attributes: map[string]string{"carrier": " au ", "storage": "128"}},
+		{name: "not a leaf", categoryID: "phones", want: []string{"category_id"}},
+		{name: "unknown category", categoryID: "nope", want: []string{"category_id"}},
+		{name: "missing required", categoryID: "smartphones", attributes: map[string]string{"storage": "64"}, want: []string{"attributes.carrier"}},
+		{name: "bad option and number", categoryID: "smartphones", attributes: map[string]string{"carrier": "other", "storage": "lots"}, want: []string{"attributes.storage", "attributes.carrier"}},
+		{name: "unknown key", categoryID: "smartphones", attributes: map[string]string{"carrier": "au", "color": "red"}, want: []string{"attributes.color"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := tt.categoryID
+			item := &model.Item{Name: "Phone", Price: 1000}
+			item.CategoryID = &id
+			item.Attributes = tt.attributes
+			got := fieldsOf(t, validateItem(item, categories))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
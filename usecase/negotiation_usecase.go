@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"context"
+	"hackathon-backend/dao"
+	"hackathon-backend/model"
+	"strings"
+)
+
+type NegotiationUsecase struct {
+	repo *dao.NegotiationRepository
+}
+
+func NewNegotiationUsecase(repo *dao.NegotiationRepository) *NegotiationUsecase {
+	return &NegotiationUsecase{repo: repo}
+}
+
+// GetPromptReport compares acceptance rate and final price across prompt versions.
+func (u *NegotiationUsecase) GetPromptReport(ctx context.Context) ([]model.PromptVersionReport, error) {
+	ctx, cancel := withDeadline(ctx, deadlines.Read)
+	defer cancel()
+	return u.repo.GetPromptVersionReport(ctx)
+}
+
+// Buckets for buyer offers as a share of the initial listing price.
+var priceBuckets = []model.PriceBucket{
+	{Label: "<50%", MinRatio: 0, MaxRatio: 0.5},
+	{Label: "50-60%", MinRatio: 0.5, MaxRatio: 0.6},
+	{Label: "60-70%", MinRatio: 0.6, MaxRatio: 0.7},
+	{Label: "70-80%", MinRatio: 0.7, MaxRatio: 0.8},
+	{Label: "80-90%", MinRatio: 0.8, MaxRatio: 0.9},
+	{Label: "90-100%", MinRatio: 0.9, MaxRatio: 1.0},
+	{Label: ">=100%", MinRatio: 1.0, MaxRatio: 0},
+}
+
+// statsAccumulator sums raw values so averages can be computed per item and overall.
+type statsAccumulator struct {
+	stats         model.NegotiationStats
+	discountSum   float64
+	discountCount int
+	hoursSum      float64
+	hoursCount    int
+}
+
+func newStatsAccumulator() *statsAccumulator {
+	buckets := make([]model.PriceBucket, len(priceBuckets))
+	copy(buckets, priceBuckets)
+	return &statsAccumulator{stats: model.NegotiationStats{
+		Decisions:                 make(map[string]int),
+		ProposedPriceDistribution: buckets,
+	}}
+}
+
+func (a *statsAccumulator) addLog(l model.NegotiationLogStat, initialPrice int) {
+	// Retries are logged as e.g. "COUNTER (RETRY)"
+	decision := strings.ToUpper(strings.TrimSpace(strings.TrimSuffix(l.AIDecision, " (RETRY)")))
+	a.stats.Decisions[decision]++
+
+	if l.ProposedPrice > 0 {
+		a.stats.Offers++
+		if initialPrice > 0 {
+			ratio := float64(l.ProposedPrice) / float64(initialPrice)
+			for i, b := range a.stats.ProposedPriceDistribution {
+				if ratio >= b.MinRatio && (b.MaxRatio == 0 || ratio < b.MaxRatio) {
+					a.stats.ProposedPriceDistribution[i].Count++
+					break
+				}
+			}
+		}
+	}
+
+	d := &a.stats.Drafts
+	switch l.Outcome {
+	case model.OutcomeApproved:
+		d.Approved++
+		if l.AutoApproved {
+			d.AutoApproved++
+		}
+	case model.OutcomeRejected:
+		d.Rejected++
+	case model.OutcomeRegenerated:
+		d.Regenerated++
+	case model.OutcomePending:
+		d.Pending++
+	default:
+		return // Logs from before outcomes were tracked
+	}
+	d.Total++
+}
+
+func (a *statsAccumulator) addSale(item model.Item) {
+	a.stats.SoldCount++
+	if item.InitialPrice > 0 {
+		a.discountSum += float64(item.InitialPrice-item.Price) / float64(item.InitialPrice)
+		a.discountCount++
+	}
+	if item.SoldAt != nil {
+		a.hoursSum += item.SoldAt.Sub(item.CreatedAt).Hours()
+		a.hoursCount++
+	}
+}
+
+func (a *statsAccumulator) finish() model.NegotiationStats {
+	if a.discountCount > 0 {
+		avg := a.discountSum / float64(a.discountCount)
+		a.stats.AvgDiscountRate = &avg
+	}
+	if a.hoursCount > 0 {
+		avg := a.hoursSum / float64(a.hoursCount)
+		a.stats.AvgTimeToSaleHours = &avg
+	}
+	d := &a.stats.Drafts
+	if d.Total > 0 {
+		d.ApprovalRate = float64(d.Approved) / float64(d.Total)
+		d.RejectionRate = float64(d.Rejected) / float64(d.Total)
+		d.RegenerationRate = float64(d.Regenerated) / float64(d.Total)
+	}
+	return a.stats
+}
+
+// GetSellerStats builds the negotiation dashboard for a seller. Only the seller may view it.
+func (u *NegotiationUsecase) GetSellerStats(ctx context.Context, sellerID string, requesterID string) (*model.SellerNegotiationStats, error) {
+	ctx, cancel := withDeadline(ctx, deadlines.Read)
+	defer cancel()
+	if requesterID == "" {
+		return nil, unauthorized()
+	}
+	if sellerID != requesterID {
+		return nil, forbidden("only the seller can view these stats")
+	}
+
+	items, err := u.repo.GetSellerItems(ctx, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	logs, err := u.repo.GetSellerLogs(ctx, sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := newStatsAccumulator()
+	perItem := make(map[string]*statsAccumulator, len(items))
+	initialPrices := make(map[string]int, len(items))
+	for _, item := range items {
+		acc := newStatsAccumulator()
+		perItem[item.ID] = acc
+		initialPrices[item.ID] = item.InitialPrice
+		if item.Status == "sold" {
+			acc.addSale(item)
+			totals.addSale(item)
+		}
+	}
+	for _, l := range logs {
+		acc, ok := perItem[l.ItemID]
+		if !ok {
+			continue
+		}
+		acc.addLog(l, initialPrices[l.ItemID])
+		totals.addLog(l, initialPrices[l.ItemID])
+	}
+
+	result := &model.SellerNegotiationStats{
+		SellerID: sellerID,
+		Totals:   totals.finish(),
+		Items:    make([]model.ItemNegotiationStats, 0, len(items)),
+	}
+	for _, item := range items {
+		result.Items = append(result.Items, model.ItemNegotiationStats{
+			ItemID:           item.ID,
+			Name:             item.Name,
+			Status:           item.Status,
+			InitialPrice:     item.InitialPrice,
+			Price:            item.Price,
+			NegotiationStats: perItem[item.ID].finish(),
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"hackathon-backend/model"
+)
+
+const notificationSnippetLength = 80
+
+// snippet shortens message content for a notification body.
+func snippet(content string) string {
+	runes := []rune(content)
+	if len(runes) <= notificationSnippetLength {
+		return content
+	}
+	return string(runes[:notificationSnippetLength]) + "…"
+}
+
+// HandleEvent is the domain event subscriber that turns marketplace activity
+// into user notifications:
+//   - draft.pending: the seller has an AI draft to review
+//   - draft.approved, or a seller's own reply: the buyer side gets the reply
+//   - item.sold: the seller and every other negotiating buyer
+//   - order.case_opened / order.case_closed: the parties to a cancellation or dispute
+func (u *NotificationUsecase) HandleEvent(ctx context.Context, e *model.DomainEvent) error {
+	switch e.Type {
+	case model.EventDraftPending:
+		ev, err := decodeMessageEvent(e)
+		if err != nil {
+			return err
+		}
+		return u.Notify(ctx, e.ID, ev.SellerID, model.NotifyDraftPending,
+			fmt.Sprintf("「%s」のAI返信案が承認待ちです", ev.ItemName),
+			snippet(ev.Message.Content), ev.ItemID, ev.Message.ID)
+
+	case model.EventDraftApproved, model.EventMessageCreated:
+		ev, err := decodeMessageEvent(e)
+		if err != nil {
+			return err
+		}
+		// AI messages are announced once, through draft.approved
+		if e.Type == model.EventMessageCreated && (ev.Message.IsAIResponse || ev.Message.SenderID != ev.SellerID) {
+			return nil
+		}
+		if ev.CounterpartID == "" || ev.CounterpartID == ev.SellerID {
+			return nil
+		}
+		return u.Notify(ctx, e.ID, ev.CounterpartID, model.NotifyReplyApproved,
+			fmt.Sprintf("「%s」の出品者から返信がありました", ev.ItemName),
+			snippet(ev.Message.Content), ev.ItemID, ev.Message.ID)
+
+	case model.EventItemSold:
+		ev, err := decodeItemEvent(e)
+		if err != nil {
+			return err
+		}
+		if err := u.Notify(ctx, e.ID, ev.Item.UserID, model.NotifyItemSold,
+			fmt.Sprintf("「%s」が購入されました", ev.Item.Name),
+			fmt.Sprintf("¥%d で売れました。", ev.Item.Price), ev.Item.ID, ""); err != nil {
+			return err
+		}
+		for _, buyerID := range ev.OtherBuyerIDs {
+			if err := u.Notify(ctx, e.ID, buyerID, model.NotifyItemSold,
+				fmt.Sprintf("交渉中の「%s」は売り切れました", ev.Item.Name),
+				"他の購入者が購入しました。", ev.Item.ID, ""); err != nil {
+				return err
+			}
+		}
+
+	case model.EventCaseOpened:
+		ev, err := decodeCaseEvent(e)
+		if err != nil {
+			return err
+		}
+		title := fmt.Sprintf("「%s」の取引キャンセルが申請されました", ev.Item.Name)
+		if ev.Case.Kind == model.CaseDispute {
+			title = fmt.Sprintf("「%s」の取引について問題が報告されました", ev.Item.Name)
+		}
+		body := caseReasonLabels[ev.Case.Reason]
+		if ev.Case.Details != "" {
+			body += ": " + snippet(ev.Case.Details)
+		}
+		return u.Notify(ctx, e.ID, ev.Case.Counterpart(), model.NotifyOrderCase, title, body, ev.Item.ID, "")
+
+	case model.EventCaseClosed:
+		ev, err := decodeCaseEvent(e)
+		if err != nil {
+			return err
+		}
+		return u.notifyCaseClosed(ctx, e.ID, &ev.Case, &ev.Item)
+	}
+	return nil
+}
+
+// notifyCaseClosed tells the party who did not close the case how it ended;
+// an admin's resolution goes to both.
+func (u *NotificationUsecase) notifyCaseClosed(ctx context.Context, eventID string, c *model.OrderCase, item *model.Item) error {
+	var title string
+	switch c.Status {
+	case model.CaseAccepted:
+		title = fmt.Sprintf("「%s」の取引キャンセルが承認されました", item.Name)
+	case model.CaseDeclined:
+		title = fmt.Sprintf("「%s」の取引キャンセルが却下されました", item.Name)
+	case model.CaseWithdrawn:
+		title = fmt.Sprintf("「%s」の申請が取り下げられました", item.Name)
+	case model.CaseResolved:
+		title = fmt.Sprintf("「%s」の問題が解決されました", item.Name)
+	default:
+		return nil
+	}
+	var body string
+	if c.Outcome != "" {
+		body = "結果: " + caseOutcomeLabels[c.Outcome]
+		if c.ResolutionNote != "" {
+			body += "\n" + snippet(c.ResolutionNote)
+		}
+	}
+	for _, userID := range []string{c.SellerID, c.BuyerID} {
+		if userID == c.ClosedBy {
+			continue
+		}
+		if err := u.Notify(ctx, eventID, userID, model.NotifyOrderCase, title, body, item.ID, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,349 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hackathon-backend/dao"
+	"hackathon-backend/model"
+	"hackathon-backend/pkg/notify"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// externalSendTimeout bounds one email / web push delivery.
+const externalSendTimeout = 15 * time.Second
+
+var notificationEvents = []string{model.NotifyDraftPending, model.NotifyReplyApproved, model.NotifyItemSold, model.NotifyPriceDrop, model.NotifyOrderCase}
+var notificationChannels = []string{model.NotifyChannelInApp, model.NotifyChannelEmail, model.NotifyChannelWebPush}
+
+// defaultPreference applies when the user has not chosen: in-app only.
+func defaultPreference(channel string) bool {
+	return channel == model.NotifyChannelInApp
+}
+
+// inAppNotifier delivers by storing the notification for the notification center.
+type inAppNotifier struct {
+	repo *dao.NotificationRepository
+}
+
+func (n *inAppNotifier) Channel() string {
+	return notify.ChannelInApp
+}
+
+// errAlreadyNotified reports an in-app notification stored by an earlier delivery of the same event.
+var errAlreadyNotified = errors.New("already notified")
+
+func (n *inAppNotifier) Send(ctx context.Context, msg notify.Notification) error {
+	created, err := n.repo.Create(ctx, &model.Notification{
+		ID:        msg.ID,
+		UserID:    msg.UserID,
+		Type:      msg.Type,
+		Title:     msg.Title,
+		Body:      msg.Body,
+		ItemID:    msg.ItemID,
+		MessageID: msg.MessageID,
+		CreatedAt: msg.CreatedAt,
+	})
+	if err == nil && !created {
+		return errAlreadyNotified
+	}
+	return err
+}
+
+type NotificationUsecase struct {
+	repo      *dao.NotificationRepository
+	userRepo  *dao.UserRepository
+	notifiers map[string]notify.Notifier
+	sending   sync.WaitGroup // Background email / web push sends
+}
+
+// NewNotificationUsecase always delivers in-app; extra notifiers (email, web push)
+// are used for users who enabled their channel.
+func NewNotificationUsecase(repo *dao.NotificationRepository, userRepo *dao.UserRepository, notifiers ...notify.Notifier) *NotificationUsecase {
+	u := &NotificationUsecase{
+		repo:      repo,
+		userRepo:  userRepo,
+		notifiers: map[string]notify.Notifier{notify.ChannelInApp: &inAppNotifier{repo: repo}},
+	}
+	for _, n := range notifiers {
+		u.notifiers[n.Channel()] = n
+	}
+	return u
+}
+
+// Notify sends one event to a user over every channel they have enabled.
+// The in-app copy is stored before returning and its failure is returned;
+// email and web push go out in the background and only log failures.
+// eventID is the domain event behind it: the notification ID is derived from
+// it, so a redelivered event neither repeats the in-app copy nor resends
+// email and web push. With in-app turned off there is no copy to check.
+func (u *NotificationUsecase) Notify(ctx context.Context, eventID string, userID string, eventType string, title string, body string, itemID string, messageID string) error {
+	if userID == "" {
+		return nil
+	}
+	enabled, err := u.enabledChannels(ctx, userID, eventType)
+	if err != nil {
+		return err
+	}
+
+	n := notify.Notification{
+		ID:        notificationID(eventID, userID),
+		UserID:    userID,
+		Type:      eventType,
+		Title:     title,
+		Body:      body,
+		ItemID:    itemID,
+		MessageID: messageID,
+		CreatedAt: time.Now(),
+	}
+
+	if enabled[notify.ChannelInApp] {
+		err := u.notifiers[notify.ChannelInApp].Send(ctx, n)
+		if errors.Is(err, errAlreadyNotified) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	var external []notify.Notifier
+	for channel, notifier := range u.notifiers {
+		if channel != notify.ChannelInApp && enabled[channel] {
+			external = append(external, notifier)
+		}
+	}
+	if len(external) > 0 {
+		u.sending.Add(1)
+		go func() {
+			defer u.sending.Done()
+			u.sendExternal(n, external)
+		}()
+	}
+	return nil
+}
+
+// Wait blocks until every email and web push send started so far has finished.
+// Call it at shutdown once nothing calls Notify any more.
+func (u *NotificationUsecase) Wait() {
+	u.sending.Wait()
+}
+
+// notificationID is a ULID with the event's timestamp and entropy from the
+// event and recipient, so it sorts like a fresh one but repeats on redelivery.
+func notificationID(eventID string, userID string) string {
+	ms := ulid.Now()
+	if id, err := ulid.Parse(eventID); err == nil {
+		ms = id.Time()
+	}
+	sum := sha256.Sum256([]byte(eventID + "/" + userID))
+	var id ulid.ULID
+	id.SetTime(ms)
+	id.SetEntropy(sum[:10])
+	return id.String()
+}
+
+func (u *NotificationUsecase) sendExternal(n notify.Notification, notifiers []notify.Notifier) {
+	// Runs after Notify returned, so it can't use the caller's context
+	ctx, cancel := withDeadline(context.Background(), deadlines.Read)
+	// Fill in the recipient details the channels need
+	if user, err := u.userRepo.GetByID(ctx, n.UserID); err == nil {
+		n.Email = user.Email
+	}
+	if subs, err := u.repo.GetPushSubscriptions(ctx, n.UserID); err == nil {
+		for _, s := range subs {
+			n.PushSubscriptions = append(n.PushSubscriptions, notify.PushSubscription{Endpoint: s.Endpoint, P256dh: s.P256dh, Auth: s.Auth})
+		}
+	}
+	cancel()
+
+	for _, notifier := range notifiers {
+		sendCtx, cancelSend := context.WithTimeout(context.Background(), externalSendTimeout)
+		err := notifier.Send(sendCtx, n)
+		cancelSend()
+
+		var gone *notify.ErrSubscriptionGone
+		if errors.As(err, &gone) {
+			delCtx, cancelDel := withDeadline(context.Background(), deadlines.Write)
+			if err := u.repo.DeletePushSubscriptionsByEndpoint(delCtx, gone.Endpoints); err != nil {
+				fmt.Println("Failed to delete expired push subscriptions:", err)
+			}
+			cancelDel()
+		}
+		if err != nil {
+			fmt.Printf("Failed to send %s notification to %s: %v\n", notifier.Channel(), n.UserID, err)
+		}
+	}
+}
+
+// enabledChannels resolves the user's preferences for one event over the defaults.
+func (u *NotificationUsecase) enabledChannels(ctx context.Context, userID string, eventType string) (map[string]bool, error) {
+	prefs, err := u.repo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	enabled := make(map[string]bool)
+	for _, channel := range notificationChannels {
+		enabled[channel] = defaultPreference(channel)
+	}
+	for _, p := range prefs {
+		if p.EventType == eventType {
+			enabled[p.Channel] = p.Enabled
+		}
+	}
+	return enabled, nil
+}
+
+// ------ Notification center ------
+
+func (u *NotificationUsecase) GetNotifications(ctx context.Context, userID string, unreadOnly bool, limit int) ([]model.Notification, int, error) {
+	ctx, cancel := withDeadline(ctx, deadlines.Read)
+	defer cancel()
+	if userID == "" {
+		return nil, 0, unauthorized()
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	notifications, err := u.repo.GetByUserID(ctx, userID, unreadOnly, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	unread, err := u.repo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return notifications, unread, nil
+}
+
+func (u *NotificationUsecase) MarkRead(ctx context.Context, notificationID string, userID string) error {
+	ctx, cancel := withDeadline(ctx, deadlines.Write)
+	defer cancel()
+	if userID == "" {
+		return unauthorized()
+	}
+	found, err := u.repo.MarkRead(ctx, notificationID, userID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return notFound("notification")
+	}
+	return nil
+}
+
+func (u *NotificationUsecase) MarkAllRead(ctx context.Context, userID string) error {
+	ctx, cancel := withDeadline(ctx, deadlines.Write)
+	defer cancel()
+	if userID == "" {
+		return unauthorized()
+	}
+	return u.repo.MarkAllRead(ctx, userID)
+}
+
+// ------ Preferences ------
+
+// GetPreferences returns the full event x channel matrix with defaults filled in.
+func (u *NotificationUsecase) GetPreferences(ctx context.Context, userID string) ([]model.NotificationPreference, error) {
+	ctx, cancel := withDeadline(ctx, deadlines.Read)
+	defer cancel()
+	if userID == "" {
+		return nil, unauthorized()
+	}
+	stored, err := u.repo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	chosen := make(map[string]bool)
+	for _, p := range stored {
+		chosen[p.EventType+"/"+p.Channel] = p.Enabled
+	}
+
+	var prefs []model.NotificationPreference
+	for _, event := range notificationEvents {
+		for _, channel := range notificationChannels {
+			enabled, ok := chosen[event+"/"+channel]
+			if !ok {
+				enabled = defaultPreference(channel)
+			}
+			prefs = append(prefs, model.NotificationPreference{UserID: userID, EventType: event, Channel: channel, Enabled: enabled})
+		}
+	}
+	return prefs, nil
+}
+
+func (u *NotificationUsecase) UpdatePreferences(ctx context.Context, userID string, prefs []model.NotificationPreference) ([]model.NotificationPreference, error) {
+	ctx, cancel := withDeadline(ctx, deadlines.Write)
+	defer cancel()
+	if userID == "" {
+		return nil, unauthorized()
+	}
+	for _, p := range prefs {
+		if !contains(notificationEvents, p.EventType) || !contains(notificationChannels, p.Channel) {
+			return nil, invalid("preferences", "invalid notification preference "+p.EventType+"/"+p.Channel)
+		}
+	}
+	for _, p := range prefs {
+		p.UserID = userID
+		if err := u.repo.UpsertPreference(ctx, &p); err != nil {
+			return nil, err
+		}
+	}
+	return u.GetPreferences(ctx, userID)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ------ Web push subscriptions ------
+
+// VAPIDPublicKey is the application server key browsers need to subscribe.
+// Empty when web push is not configured.
+func (u *NotificationUsecase) VAPIDPublicKey() string {
+	if p, ok := u.notifiers[notify.ChannelWebPush].(interface{ PublicKey() string }); ok {
+		return p.PublicKey()
+	}
+	return ""
+}
+
+func (u *NotificationUsecase) Subscribe(ctx context.Context, userID string, endpoint string, p256dh string, auth string) error {
+	ctx, cancel := withDeadline(ctx, deadlines.Write)
+	defer cancel()
+	if userID == "" {
+		return unauthorized()
+	}
+	if endpoint == "" {
+		return invalid("endpoint", "invalid push subscription: endpoint required")
+	}
+	if p256dh == "" || auth == "" {
+		return invalid("keys", "invalid push subscription: keys.p256dh and keys.auth required")
+	}
+	return u.repo.SavePushSubscription(ctx, &model.PushSubscription{
+		ID:        ulid.MustNew(ulid.Now(), ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)).String(),
+		UserID:    userID,
+		Endpoint:  endpoint,
+		P256dh:    p256dh,
+		Auth:      auth,
+		CreatedAt: time.Now(),
+	})
+}
+
+func (u *NotificationUsecase) Unsubscribe(ctx context.Context, userID string, endpoint string) error {
+	ctx, cancel := withDeadline(ctx, deadlines.Write)
+	defer cancel()
+	if userID == "" {
+		return unauthorized()
+	}
+	return u.repo.DeletePushSubscription(ctx, userID, endpoint)
+}
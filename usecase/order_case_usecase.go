@@ -0,0 +1,437 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"hackathon-backend/dao"
+	"hackathon-backend/model"
+	"math/rand"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	maxCaseTextLength = 2000
+	adminCaseListSize = 100
+)
+
+var caseReasonLabels = map[string]string{
+	"changed_mind":     "購入者都合",
+	"cannot_ship":      "発送できない",
+	"wrong_price":      "価格の誤り",
+	"not_received":     "商品が届かない",
+	"not_as_described": "説明と異なる",
+	"damaged":          "破損・汚損",
+	"unresponsive":     "相手から連絡がない",
+	"other":            "その他",
+}
+
+var caseOutcomeLabels = map[string]string{
+	model.OutcomeRefund:   "返金",
+	model.OutcomeComplete: "取引完了",
+	model.OutcomeRelist:   "再出品",
+}
+
+// OrderCaseUsecase handles what happens after a sale: cancellation requests
+// settled by mutual consent, and disputes settled by an admin. Admins are the
+// user IDs in ADMIN_USER_IDS.
+type OrderCaseUsecase struct {
+	repo     *dao.OrderCaseRepository
+	itemRepo *dao.ItemRepository
+	txm      *dao.TxManager
+	events   *EventDispatcher
+	admins   map[string]bool
+}
+
+func NewOrderCaseUsecase(repo *dao.OrderCaseRepository, itemRepo *dao.ItemRepository, txm *dao.TxManager, events *EventDispatcher, adminIDs []string) *OrderCaseUsecase {
+	admins := make(map[string]bool)
+	for _, id := range adminIDs {
+		admins[id] = true
+	}
+	return &OrderCaseUsecase{repo: repo, itemRepo: itemRepo, txm: txm, events: events, admins: admins}
+}
+
+func (u *OrderCaseUsecase) isAdmin(userID string) bool {
+	return userID != "" && u.admins[userID]
+}
+
+// inTx runs fn in a transaction bounded by the write deadline and wakes the
+// event dispatcher once it commits.
+func (u *OrderCaseUsecase) inTx(ctx context.Context, fn func(tx *dao.Tx) error) error {
+	ctx, cancel := withDeadline(ctx, deadlines.Write)
+	defer cancel()
+	if err := u.txm.WithTx(ctx, fn); err != nil {
+		return err
+	}
+	u.events.Wake()
+	return nil
+}
+
+// ------ Opening ------
+
+// RequestCancellation asks the other party to call the sale off.
+func (u *OrderCaseUsecase) RequestCancellation(ctx context.Context, itemID string, userID string, reason string, details string) (*model.OrderCase, error) {
+	return u.open(ctx, model.CaseCancellation, itemID, userID, reason, details)
+}
+
+// OpenDispute reports a problem with the order for an admin to settle.
+func (u *OrderCaseUsecase) OpenDispute(ctx context.Context, itemID string, userID string, reason string, details string) (*model.OrderCase, error) {
+	return u.open(ctx, model.CaseDispute, itemID, userID, reason, details)
+}
+
+func (u *OrderCaseUsecase) open(ctx context.Context, kind string, itemID string, userID string, reason string, details string) (*model.OrderCase, error) {
+	if userID == "" {
+		return nil, unauthorized()
+	}
+	details = strings.TrimSpace(details)
+	if err := validateCase(kind, reason, details); err != nil {
+		return nil, err
+	}
+
+	var c *model.OrderCase
+	err := u.inTx(ctx, func(tx *dao.Tx) error {
+		// The item row lock serialises cases on the same sale
+		item, err := tx.Items.GetByIDForUpdate(ctx, itemID)
+		if err != nil {
+			return err
+		}
+		if item == nil || item.Status == "deleted" {
+			return notFound("item")
+		}
+		if item.Status != "sold" || item.BuyerID == nil {
+			return conflict("the item has not been purchased")
+		}
+		if userID != item.UserID && userID != *item.BuyerID {
+			return forbidden("only the seller and the buyer can open a case")
+		}
+		open, err := tx.Cases.GetOpenByItemID(ctx, itemID)
+		if err != nil {
+			return err
+		}
+		if open != nil {
+			return conflict(fmt.Sprintf("this order already has an open %s", open.Kind))
+		}
+
+		c = &model.OrderCase{
+			ID:        ulid.MustNew(ulid.Now(), ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)).String(),
+			ItemID:    itemID,
+			Kind:      kind,
+			Status:    model.CaseOpen,
+			SellerID:  item.UserID,
+			BuyerID:   *item.BuyerID,
+			OpenedBy:  userID,
+			Reason:    reason,
+			Details:   details,
+			CreatedAt: time.Now(),
+		}
+		if err := tx.Cases.Insert(ctx, c); err != nil {
+			return err
+		}
+		return emitEvent(ctx, tx, model.EventCaseOpened, itemID, model.CaseEvent{Case: *c, Item: *item})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func validateCase(kind string, reason string, details string) error {
+	reasons := model.CancellationReasons
+	if kind == model.CaseDispute {
+		reasons = model.DisputeReasons
+	}
+	var fields []FieldError
+	if !contains(reasons, reason) {
+		fields = append(fields, FieldError{Field: "reason", Message: "reason must be one of " + strings.Join(reasons, ", ")})
+	}
+	if details == "" && (reason == "other" || kind == model.CaseDispute) {
+		fields = append(fields, FieldError{Field: "details", Message: "describe what happened"})
+	}
+	if utf8.RuneCountInString(details) > maxCaseTextLength {
+		fields = append(fields, FieldError{Field: "details", Message: fmt.Sprintf("details must be at most %d characters", maxCaseTextLength)})
+	}
+	if len(fields) > 0 {
+		return &Error{Kind: ErrValidation, Message: fields[0].Message, Fields: fields}
+	}
+	return nil
+}
+
+// ------ Reading ------
+
+// GetItemCases lists an order's cases for its seller, its buyer or an admin.
+func (u *OrderCaseUsecase) GetItemCases(ctx context.Context, itemID string, userID string) ([]model.OrderCase, error) {
+	if userID == "" {
+		return nil, unauthorized()
+	}
+	ctx, cancel := withDeadline(ctx, deadlines.Read)
+	defer cancel()
+	item, err := u.itemRepo.GetByID(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil || item.Status == "deleted" {
+		return nil, notFound("item")
+	}
+	cases, err := u.repo.GetByItemID(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	isBuyer := item.BuyerID != nil && *item.BuyerID == userID
+	// Past buyers of a relisted item still see the cases they were party to
+	visible := cases[:0]
+	for _, c := range cases {
+		if c.IsParty(userID) || u.isAdmin(userID) {
+			visible = append(visible, c)
+		}
+	}
+	if len(visible) == 0 && userID != item.UserID && !isBuyer && !u.isAdmin(userID) {
+		return nil, forbidden("only the seller and the buyer can see the order's cases")
+	}
+	return visible, nil
+}
+
+// GetCase returns a case with its messages.
+func (u *OrderCaseUsecase) GetCase(ctx context.Context, caseID string, userID string) (*model.OrderCase, error) {
+	ctx, cancel := withDeadline(ctx, deadlines.Read)
+	defer cancel()
+	c, err := u.visibleCase(ctx, caseID, userID)
+	if err != nil {
+		return nil, err
+	}
+	c.Messages, err = u.repo.GetMessages(ctx, c.ID)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ListCases is the admin queue: cases of a kind in a status, oldest first.
+func (u *OrderCaseUsecase) ListCases(ctx context.Context, userID string, kind string, status string) ([]model.OrderCase, error) {
+	if userID == "" {
+		return nil, unauthorized()
+	}
+	if !u.isAdmin(userID) {
+		return nil, forbidden("admins only")
+	}
+	if kind == "" {
+		kind = model.CaseDispute
+	}
+	if status == "" {
+		status = model.CaseOpen
+	}
+	if kind != model.CaseDispute && kind != model.CaseCancellation {
+		return nil, invalid("kind", "kind must be dispute or cancellation")
+	}
+	ctx, cancel := withDeadline(ctx, deadlines.Read)
+	defer cancel()
+	return u.repo.GetByStatus(ctx, kind, status, adminCaseListSize)
+}
+
+// visibleCase loads a case its parties and admins may see.
+func (u *OrderCaseUsecase) visibleCase(ctx context.Context, caseID string, userID string) (*model.OrderCase, error) {
+	if userID == "" {
+		return nil, unauthorized()
+	}
+	c, err := u.repo.GetByID(ctx, caseID)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, notFound("case")
+	}
+	if !c.IsParty(userID) && !u.isAdmin(userID) {
+		return nil, forbidden("only the seller, the buyer and admins can see this case")
+	}
+	return c, nil
+}
+
+// ------ Messages ------
+
+// AddMessage adds a statement, optionally with evidence, to an open case.
+func (u *OrderCaseUsecase) AddMessage(ctx context.Context, caseID string, userID string, content string, evidenceURL string) (*model.CaseMessage, error) {
+	ctx, cancel := withDeadline(ctx, deadlines.Write)
+	defer cancel()
+	c, err := u.visibleCase(ctx, caseID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if c.Status != model.CaseOpen {
+		return nil, conflict("the case is closed")
+	}
+	content = strings.TrimSpace(content)
+	var fields []FieldError
+	if content == "" {
+		fields = append(fields, FieldError{Field: "content", Message: "content is required"})
+	} else if utf8.RuneCountInString(content) > maxCaseTextLength {
+		fields = append(fields, FieldError{Field: "content", Message: fmt.Sprintf("content must be at most %d characters", maxCaseTextLength)})
+	}
+	if evidenceURL != "" {
+		parsed, err := url.Parse(evidenceURL)
+		if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" || len(evidenceURL) > maxImageURLLength {
+			fields = append(fields, FieldError{Field: "evidence_url", Message: "evidence_url must be an http(s) URL"})
+		}
+	}
+	if len(fields) > 0 {
+		return nil, &Error{Kind: ErrValidation, Message: fields[0].Message, Fields: fields}
+	}
+
+	msg := &model.CaseMessage{
+		ID:          ulid.MustNew(ulid.Now(), ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)).String(),
+		CaseID:      c.ID,
+		SenderID:    userID,
+		Content:     content,
+		EvidenceURL: evidenceURL,
+		CreatedAt:   time.Now(),
+	}
+	if err := u.repo.InsertMessage(ctx, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// ------ Closing ------
+
+// AcceptCancellation is the other party agreeing to call the sale off; the item goes back on sale.
+func (u *OrderCaseUsecase) AcceptCancellation(ctx context.Context, caseID string, userID string) (*model.OrderCase, error) {
+	return u.close(ctx, caseID, userID, func(c *model.OrderCase) error {
+		if c.Kind != model.CaseCancellation {
+			return conflict("only cancellation requests can be accepted")
+		}
+		if userID != c.Counterpart() {
+			return forbidden("only the other party can accept the cancellation")
+		}
+		c.Status = model.CaseAccepted
+		c.Outcome = model.OutcomeRelist
+		return nil
+	})
+}
+
+// DeclineCancellation keeps the sale; the requester may still open a dispute.
+func (u *OrderCaseUsecase) DeclineCancellation(ctx context.Context, caseID string, userID string) (*model.OrderCase, error) {
+	return u.close(ctx, caseID, userID, func(c *model.OrderCase) error {
+		if c.Kind != model.CaseCancellation {
+			return conflict("only cancellation requests can be declined")
+		}
+		if userID != c.Counterpart() {
+			return forbidden("only the other party can decline the cancellation")
+		}
+		c.Status = model.CaseDeclined
+		return nil
+	})
+}
+
+// Withdraw closes a case on behalf of whoever opened it, leaving the sale as it is.
+func (u *OrderCaseUsecase) Withdraw(ctx context.Context, caseID string, userID string) (*model.OrderCase, error) {
+	return u.close(ctx, caseID, userID, func(c *model.OrderCase) error {
+		if userID != c.OpenedBy {
+			return forbidden("only whoever opened the case can withdraw it")
+		}
+		c.Status = model.CaseWithdrawn
+		return nil
+	})
+}
+
+// Resolve settles a dispute. Only admins who are not party to the order may.
+func (u *OrderCaseUsecase) Resolve(ctx context.Context, caseID string, userID string, outcome string, note string) (*model.OrderCase, error) {
+	note = strings.TrimSpace(note)
+	if _, ok := caseOutcomeLabels[outcome]; !ok {
+		return nil, invalid("outcome", "outcome must be refund, complete or relist")
+	}
+	if utf8.RuneCountInString(note) > maxCaseTextLength {
+		return nil, invalid("note", fmt.Sprintf("note must be at most %d characters", maxCaseTextLength))
+	}
+	return u.close(ctx, caseID, userID, func(c *model.OrderCase) error {
+		if !u.isAdmin(userID) {
+			return forbidden("admins only")
+		}
+		if c.IsParty(userID) {
+			return forbidden("admins cannot resolve their own orders")
+		}
+		if c.Kind != model.CaseDispute {
+			return conflict("cancellation requests are settled by the seller and the buyer")
+		}
+		c.Status = model.CaseResolved
+		c.Outcome = outcome
+		c.ResolutionNote = note
+		return nil
+	})
+}
+
+// close loads an open case with its item locked, lets decide set the new
+// status and outcome, and applies the outcome to the sale.
+func (u *OrderCaseUsecase) close(ctx context.Context, caseID string, userID string, decide func(c *model.OrderCase) error) (*model.OrderCase, error) {
+	if userID == "" {
+		return nil, unauthorized()
+	}
+	var c *model.OrderCase
+	err := u.inTx(ctx, func(tx *dao.Tx) error {
+		var err error
+		c, err = tx.Cases.GetByID(ctx, caseID)
+		if err != nil {
+			return err
+		}
+		if c == nil {
+			return notFound("case")
+		}
+		if !c.IsParty(userID) && !u.isAdmin(userID) {
+			return forbidden("only the seller, the buyer and admins can act on this case")
+		}
+		item, err := tx.Items.GetByIDForUpdate(ctx, c.ItemID)
+		if err != nil {
+			return err
+		}
+		if item == nil {
+			return notFound("item")
+		}
+		// Re-read under the item lock, which every change to the item's cases takes
+		c, err = tx.Cases.GetByID(ctx, caseID)
+		if err != nil {
+			return err
+		}
+		if c.Status != model.CaseOpen {
+			return conflict("the case is already " + c.Status)
+		}
+		if err := decide(c); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		c.ClosedBy = userID
+		c.ClosedAt = &now
+		if err := tx.Cases.Close(ctx, c); err != nil {
+			return err
+		}
+		if err := settleSale(ctx, tx, item, c.Outcome); err != nil {
+			return err
+		}
+		return emitEvent(ctx, tx, model.EventCaseClosed, item.ID, model.CaseEvent{Case: *c, Item: *item})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// settleSale applies a case outcome to the item. Reversing the sale drops the
+// buyer's shipping address; relisting also clears the buyer.
+func settleSale(ctx context.Context, tx *dao.Tx, item *model.Item, outcome string) error {
+	switch outcome {
+	case model.OutcomeRelist:
+		item.Status = model.ItemStatusOnSale
+		item.BuyerID = nil
+		item.SoldAt = nil
+		item.ShippingFee = nil
+	case model.OutcomeRefund:
+		item.Status = model.ItemStatusCancelled
+	default:
+		return nil
+	}
+	if err := tx.Items.Update(ctx, item); err != nil {
+		return err
+	}
+	return tx.Addresses.DeletePurchase(ctx, item.ID)
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"hackathon-backend/model"
+	"hackathon-backend/pkg/sse"
+)
+
+// StreamUsecase pushes live updates to users' open SSE connections.
+type StreamUsecase struct {
+	hub *sse.Hub
+}
+
+func NewStreamUsecase(hub *sse.Hub) *StreamUsecase {
+	return &StreamUsecase{hub: hub}
+}
+
+// streamPayload is the data of one SSE event.
+type streamPayload struct {
+	ItemID  string          `json:"item_id"`
+	Item    *itemSummary    `json:"item,omitempty"`
+	Message *messageSummary `json:"message,omitempty"`
+}
+
+// Subscribe opens a stream for a user.
+func (u *StreamUsecase) Subscribe(userID string) (<-chan sse.Event, func(), error) {
+	if userID == "" {
+		return nil, nil, unauthorized()
+	}
+	events, cancel := u.hub.Subscribe(userID)
+	return events, cancel, nil
+}
+
+// Close ends all open streams so the server can shut down.
+func (u *StreamUsecase) Close() {
+	u.hub.Close()
+}
+
+// HandleEvent is the domain event subscriber that forwards events to the users who may see them.
+func (u *StreamUsecase) HandleEvent(ctx context.Context, e *model.DomainEvent) error {
+	var payload streamPayload
+	var audience []string
+
+	switch e.Type {
+	case model.EventItemSold:
+		ev, err := decodeItemEvent(e)
+		if err != nil {
+			return err
+		}
+		item := toItemSummary(&ev.Item)
+		payload = streamPayload{ItemID: ev.Item.ID, Item: &item}
+		audience = append([]string{ev.Item.UserID}, ev.OtherBuyerIDs...)
+		if ev.Item.BuyerID != nil {
+			audience = append(audience, *ev.Item.BuyerID)
+		}
+	case model.EventMessageCreated, model.EventDraftPending, model.EventDraftApproved:
+		ev, err := decodeMessageEvent(e)
+		if err != nil {
+			return err
+		}
+		msg := toMessageSummary(&ev.Message)
+		payload = streamPayload{ItemID: ev.ItemID, Message: &msg}
+		audience = ev.Audience()
+	default:
+		return nil
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	for _, userID := range audience {
+		u.hub.Publish(userID, sse.Event{ID: e.ID, Type: e.Type, Data: data})
+	}
+	return nil
+}
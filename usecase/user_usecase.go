@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"hackathon-backend/dao"
 	"hackathon-backend/model"
 	"math/rand"
@@ -17,16 +18,18 @@ func NewUserUsecase(repo *dao.UserRepository) *UserUsecase {
 	return &UserUsecase{repo: repo}
 }
 
-func (u *UserUsecase) RegisterUser(id, name, email string) (*model.User, error) {
+func (u *UserUsecase) RegisterUser(ctx context.Context, id, name, email string) (*model.User, error) {
+	ctx, cancel := withDeadline(ctx, deadlines.Write)
+	defer cancel()
 	// 1. If ID provided (Firebase Auth), check by ID
 	if id != "" {
-		existingUser, err := u.repo.GetByID(id)
+		existingUser, err := u.repo.GetByID(ctx, id)
 		if err == nil && existingUser != nil {
 			// Update Name/Email if changed (Upsert-like behavior)
 			if existingUser.Name != name || existingUser.Email != email {
 				existingUser.Name = name
 				existingUser.Email = email
-				if err := u.repo.Update(existingUser); err != nil {
+				if err := u.repo.Update(ctx, existingUser); err != nil {
 					return nil, err
 				}
 			}
@@ -34,7 +37,7 @@ func (u *UserUsecase) RegisterUser(id, name, email string) (*model.User, error)
 		}
 	} else {
 		// Fallback: Check by Email (Legacy)
-		existingUser, err := u.repo.GetByEmail(email)
+		existingUser, err := u.repo.GetByEmail(ctx, email)
 		if err == nil && existingUser != nil {
 			return existingUser, nil
 		}
@@ -53,7 +56,7 @@ func (u *UserUsecase) RegisterUser(id, name, email string) (*model.User, error)
 		Email: email,
 	}
 
-	if err := u.repo.Insert(user); err != nil {
+	if err := u.repo.Insert(ctx, user); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"hackathon-backend/dao"
+	"hackathon-backend/model"
+)
+
+// WatchUsecase manages watches and likes and sends the price-drop alerts they
+// subscribe to. Views are anonymous, so only watches and likes count.
+type WatchUsecase struct {
+	repo          *dao.WatchRepository
+	itemRepo      *dao.ItemRepository
+	notifications *NotificationUsecase
+}
+
+func NewWatchUsecase(repo *dao.WatchRepository, itemRepo *dao.ItemRepository, notifications *NotificationUsecase) *WatchUsecase {
+	return &WatchUsecase{repo: repo, itemRepo: itemRepo, notifications: notifications}
+}
+
+// Watch subscribes the user to price drops on the item, or, with a target
+// price, to the price reaching it.
+func (u *WatchUsecase) Watch(ctx context.Context, itemID string, userID string, targetPrice *int) (*model.ItemWatch, error) {
+	ctx, cancel := withDeadline(ctx, deadlines.Write)
+	defer cancel()
+	item, err := u.watchableItem(ctx, itemID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if targetPrice != nil && (*targetPrice < minItemPrice || *targetPrice >= item.Price) {
+		return nil, invalid("target_price", fmt.Sprintf("target_price must be between %d and %d", minItemPrice, item.Price-1))
+	}
+	if err := u.repo.Watch(ctx, itemID, userID, targetPrice); err != nil {
+		return nil, err
+	}
+	return u.repo.Get(ctx, itemID, userID)
+}
+
+func (u *WatchUsecase) Unwatch(ctx context.Context, itemID string, userID string) error {
+	if userID == "" {
+		return unauthorized()
+	}
+	ctx, cancel := withDeadline(ctx, deadlines.Write)
+	defer cancel()
+	return u.repo.Unwatch(ctx, itemID, userID)
+}
+
+// Like marks the item as liked, which also subscribes to its price drops.
+func (u *WatchUsecase) Like(ctx context.Context, itemID string, userID string) (*model.ItemWatch, error) {
+	ctx, cancel := withDeadline(ctx, deadlines.Write)
+	defer cancel()
+	if _, err := u.watchableItem(ctx, itemID, userID); err != nil {
+		return nil, err
+	}
+	if err := u.repo.SetLiked(ctx, itemID, userID, true); err != nil {
+		return nil, err
+	}
+	return u.repo.Get(ctx, itemID, userID)
+}
+
+func (u *WatchUsecase) Unlike(ctx context.Context, itemID string, userID string) error {
+	if userID == "" {
+		return unauthorized()
+	}
+	ctx, cancel := withDeadline(ctx, deadlines.Write)
+	defer cancel()
+	return u.repo.SetLiked(ctx, itemID, userID, false)
+}
+
+// GetWatches lists the items the user watches or likes.
+func (u *WatchUsecase) GetWatches(ctx context.Context, userID string) ([]model.ItemWatch, error) {
+	if userID == "" {
+		return nil, unauthorized()
+	}
+	ctx, cancel := withDeadline(ctx, deadlines.Read)
+	defer cancel()
+	return u.repo.GetByUserID(ctx, userID)
+}
+
+// watchableItem loads an item a buyer may watch: on sale and not their own.
+func (u *WatchUsecase) watchableItem(ctx context.Context, itemID string, userID string) (*model.Item, error) {
+	if userID == "" {
+		return nil, unauthorized()
+	}
+	item, err := u.itemRepo.GetByID(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil || item.Status == "deleted" {
+		return nil, notFound("item")
+	}
+	if item.UserID == userID {
+		return nil, forbidden("sellers cannot watch their own items")
+	}
+	if item.Status != "on_sale" {
+		return nil, conflict("only items on sale can be watched")
+	}
+	return item, nil
+}
+
+// HandleEvent is the item.price_changed subscriber that alerts watchers and likers
+// to drops and resets their alerts when the price goes back up.
+func (u *WatchUsecase) HandleEvent(ctx context.Context, e *model.DomainEvent) error {
+	if e.Type != model.EventPriceChanged {
+		return nil
+	}
+	ev, err := decodePriceChangedEvent(e)
+	if err != nil {
+		return err
+	}
+	if ev.NewPrice > ev.OldPrice {
+		// e.g. a revoked negotiation: watchers alerted at the lower price should hear of it again
+		return u.repo.ClearNotifiedBelow(ctx, ev.Item.ID, ev.NewPrice)
+	}
+	if ev.NewPrice == ev.OldPrice {
+		return nil
+	}
+	// A sold item, or a price that has moved on, is no longer news; a later
+	// event covers the newer price.
+	item, err := u.itemRepo.GetByID(ctx, ev.Item.ID)
+	if err != nil {
+		return err
+	}
+	if item == nil || item.Status != "on_sale" || item.Price != ev.NewPrice {
+		return nil
+	}
+
+	watches, err := u.repo.GetByItemID(ctx, item.ID)
+	if err != nil {
+		return err
+	}
+	for _, w := range watches {
+		if w.UserID == item.UserID || !priceAlertDue(&w, ev.OldPrice, ev.NewPrice) {
+			continue
+		}
+		// Claimed per user before sending, so a retried event skips those already alerted
+		claimed, err := u.repo.ClaimAlert(ctx, item.ID, w.UserID, ev.NewPrice)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+		body := fmt.Sprintf("¥%d → ¥%d に値下がりしました。", ev.OldPrice, ev.NewPrice)
+		if w.TargetPrice != nil {
+			body = fmt.Sprintf("¥%d になり、希望価格 ¥%d に届きました。", ev.NewPrice, *w.TargetPrice)
+		}
+		if err := u.notifications.Notify(ctx, e.ID, w.UserID, model.NotifyPriceDrop,
+			fmt.Sprintf("「%s」が値下がりしました", item.Name), body, item.ID, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// priceAlertDue decides whether a drop from oldPrice to newPrice is worth an
+// alert: every drop without a target, otherwise reaching the target. Nobody is
+// alerted twice for the same or a higher price.
+func priceAlertDue(w *model.ItemWatch, oldPrice int, newPrice int) bool {
+	if newPrice >= oldPrice {
+		return false
+	}
+	if w.NotifiedPrice != nil && newPrice >= *w.NotifiedPrice {
+		return false
+	}
+	if w.TargetPrice != nil {
+		return newPrice <= *w.TargetPrice
+	}
+	return true
+}
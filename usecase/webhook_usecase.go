@@ -0,0 +1,303 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hackathon-backend/dao"
+	"hackathon-backend/model"
+	"hackathon-backend/pkg/retry"
+	"hackathon-backend/pkg/webhook"
+	"math/rand"
+	"net/url"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	webhookMaxAttempts  = 8 // ~1m, 2m, 4m ... 1h between tries; gives up after about 2 hours
+	webhookRetryBase    = time.Minute
+	webhookRetryMax     = time.Hour
+	webhookSendTimeout  = 10 * time.Second
+	webhookLease        = 2 * time.Minute // Longer than one send, so a claimed delivery isn't picked twice
+	webhookPollInterval = 5 * time.Second
+	webhookBatchSize    = 20
+)
+
+var webhookEvents = []string{model.EventItemCreated, model.EventItemSold, model.EventPriceChanged, model.EventMessageCreated, model.EventDraftApproved, model.EventCaseOpened, model.EventCaseClosed}
+
+// webhookEnvelope is the JSON body of every delivery.
+type webhookEnvelope struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+type WebhookUsecase struct {
+	repo   *dao.WebhookRepository
+	sender *webhook.Sender
+}
+
+func NewWebhookUsecase(repo *dao.WebhookRepository) *WebhookUsecase {
+	return &WebhookUsecase{repo: repo, sender: webhook.NewSender(webhookSendTimeout)}
+}
+
+// ------ Endpoint registration ------
+
+func (u *WebhookUsecase) RegisterEndpoint(ctx context.Context, userID string, endpointURL string, eventTypes []string) (*model.WebhookEndpoint, error) {
+	ctx, cancel := withDeadline(ctx, deadlines.Write)
+	defer cancel()
+	if userID == "" {
+		return nil, unauthorized()
+	}
+	parsed, err := url.Parse(endpointURL)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return nil, invalid("url", "invalid webhook url")
+	}
+	if err := webhook.CheckHost(ctx, parsed.Hostname()); err != nil {
+		if errors.Is(err, webhook.ErrPrivateAddress) {
+			return nil, invalid("url", "webhook url must point to a public address")
+		}
+		return nil, invalid("url", "webhook host could not be resolved")
+	}
+	for _, t := range eventTypes {
+		if !contains(webhookEvents, t) {
+			return nil, invalid("event_types", "unknown event type "+t)
+		}
+	}
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := &model.WebhookEndpoint{
+		ID:         ulid.MustNew(ulid.Now(), ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)).String(),
+		UserID:     userID,
+		URL:        endpointURL,
+		Secret:     secret,
+		EventTypes: eventTypes,
+		IsActive:   true,
+		CreatedAt:  time.Now(),
+	}
+	if endpoint.EventTypes == nil {
+		endpoint.EventTypes = []string{}
+	}
+	if err := u.repo.CreateEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+func (u *WebhookUsecase) GetEndpoints(ctx context.Context, userID string) ([]model.WebhookEndpoint, error) {
+	ctx, cancel := withDeadline(ctx, deadlines.Read)
+	defer cancel()
+	if userID == "" {
+		return nil, unauthorized()
+	}
+	return u.repo.GetEndpointsByUserID(ctx, userID)
+}
+
+// ownedEndpoint loads an endpoint and checks it belongs to userID.
+func (u *WebhookUsecase) ownedEndpoint(ctx context.Context, endpointID string, userID string) (*model.WebhookEndpoint, error) {
+	endpoint, err := u.repo.GetEndpointByID(ctx, endpointID)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint == nil {
+		return nil, notFound("webhook")
+	}
+	if endpoint.UserID != userID {
+		return nil, forbidden("not your webhook")
+	}
+	return endpoint, nil
+}
+
+func (u *WebhookUsecase) DeleteEndpoint(ctx context.Context, endpointID string, userID string) error {
+	ctx, cancel := withDeadline(ctx, deadlines.Write)
+	defer cancel()
+	if _, err := u.ownedEndpoint(ctx, endpointID, userID); err != nil {
+		return err
+	}
+	return u.repo.DeleteEndpoint(ctx, endpointID)
+}
+
+// GetDeliveries is the delivery log of an endpoint: the latest deliveries with every attempt.
+func (u *WebhookUsecase) GetDeliveries(ctx context.Context, endpointID string, userID string) ([]model.WebhookDelivery, error) {
+	ctx, cancel := withDeadline(ctx, deadlines.Read)
+	defer cancel()
+	if _, err := u.ownedEndpoint(ctx, endpointID, userID); err != nil {
+		return nil, err
+	}
+	return u.repo.GetDeliveriesByEndpoint(ctx, endpointID, 50)
+}
+
+// RedeliverDelivery queues a failed (or still retrying) delivery for an immediate attempt.
+func (u *WebhookUsecase) RedeliverDelivery(ctx context.Context, endpointID string, deliveryID string, userID string) error {
+	ctx, cancel := withDeadline(ctx, deadlines.Write)
+	defer cancel()
+	if _, err := u.ownedEndpoint(ctx, endpointID, userID); err != nil {
+		return err
+	}
+	found, err := u.repo.RetryDelivery(ctx, deliveryID, endpointID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !found {
+		return notFound("delivery")
+	}
+	return nil
+}
+
+// ------ Publishing ------
+
+// HandleEvent is the domain event subscriber that turns events into webhook deliveries.
+func (u *WebhookUsecase) HandleEvent(ctx context.Context, e *model.DomainEvent) error {
+	switch e.Type {
+	case model.EventItemCreated, model.EventItemSold:
+		ev, err := decodeItemEvent(e)
+		if err != nil {
+			return err
+		}
+		recipients := []string{ev.Item.UserID}
+		if ev.Item.BuyerID != nil {
+			recipients = append(recipients, *ev.Item.BuyerID)
+		}
+		return u.Publish(ctx, e.ID, e.Type, e.CreatedAt, toItemSummary(&ev.Item), recipients...)
+	case model.EventPriceChanged:
+		ev, err := decodePriceChangedEvent(e)
+		if err != nil {
+			return err
+		}
+		data := priceChangeSummary{Item: toItemSummary(&ev.Item), OldPrice: ev.OldPrice, NewPrice: ev.NewPrice, Source: ev.Source}
+		return u.Publish(ctx, e.ID, e.Type, e.CreatedAt, data, ev.Item.UserID)
+	case model.EventMessageCreated:
+		ev, err := decodeMessageEvent(e)
+		if err != nil {
+			return err
+		}
+		return u.Publish(ctx, e.ID, e.Type, e.CreatedAt, toMessageSummary(&ev.Message), ev.Audience()...)
+	case model.EventDraftApproved:
+		ev, err := decodeMessageEvent(e)
+		if err != nil {
+			return err
+		}
+		return u.Publish(ctx, e.ID, e.Type, e.CreatedAt, toMessageSummary(&ev.Message), ev.SellerID, ev.CounterpartID)
+	case model.EventCaseOpened, model.EventCaseClosed:
+		ev, err := decodeCaseEvent(e)
+		if err != nil {
+			return err
+		}
+		data := caseSummary{Case: ev.Case, Item: toItemSummary(&ev.Item)}
+		return u.Publish(ctx, e.ID, e.Type, e.CreatedAt, data, ev.Case.SellerID, ev.Case.BuyerID)
+	}
+	return nil
+}
+
+// Publish queues an event for the endpoints of each interested user. The rows
+// are the outbox: delivery happens later in RunDispatcher, so a slow or down
+// receiver never blocks the caller. eventID is shared by every delivery of the
+// event and lets receivers deduplicate.
+func (u *WebhookUsecase) Publish(ctx context.Context, eventID string, eventType string, occurredAt time.Time, data interface{}, userIDs ...string) error {
+	now := time.Now()
+	payload, err := json.Marshal(webhookEnvelope{ID: eventID, Type: eventType, CreatedAt: occurredAt, Data: data})
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	for _, userID := range userIDs {
+		if userID == "" || seen[userID] {
+			continue
+		}
+		seen[userID] = true
+
+		endpoints, err := u.repo.GetEndpointsByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		for _, e := range endpoints {
+			if !e.IsActive || (len(e.EventTypes) > 0 && !contains(e.EventTypes, eventType)) {
+				continue
+			}
+			delivery := &model.WebhookDelivery{
+				ID:            ulid.MustNew(ulid.Now(), ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)).String(),
+				EndpointID:    e.ID,
+				EventID:       eventID,
+				EventType:     eventType,
+				Payload:       string(payload),
+				Status:        model.DeliveryPending,
+				NextAttemptAt: now,
+				CreatedAt:     now,
+			}
+			if err := u.repo.CreateDelivery(ctx, delivery); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ------ Dispatcher ------
+
+// RunDispatcher delivers queued webhooks until ctx is cancelled.
+func (u *WebhookUsecase) RunDispatcher(ctx context.Context) {
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+	for {
+		u.dispatchDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (u *WebhookUsecase) dispatchDue(ctx context.Context) {
+	now := time.Now()
+	due, err := u.repo.ClaimDueDeliveries(ctx, now, now.Add(webhookLease), webhookBatchSize)
+	if err != nil {
+		fmt.Println("Failed to load webhook deliveries:", err)
+		return
+	}
+	for i := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		// A delivery in flight is finished (and recorded) even when shutdown begins
+		u.deliver(context.WithoutCancel(ctx), &due[i])
+	}
+}
+
+func (u *WebhookUsecase) deliver(ctx context.Context, d *model.WebhookDelivery) {
+	res := u.sender.Send(ctx, d.URL, d.Secret, d.EventID, d.EventType, []byte(d.Payload))
+	now := time.Now()
+
+	attempt := &model.WebhookAttempt{
+		ID:          ulid.MustNew(ulid.Now(), ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)).String(),
+		DeliveryID:  d.ID,
+		StatusCode:  res.StatusCode,
+		DurationMs:  int(res.Duration.Milliseconds()),
+		AttemptedAt: now,
+	}
+	d.Attempts++
+	if res.OK() {
+		d.Status = model.DeliveryDelivered
+		d.LastError = ""
+		d.DeliveredAt = &now
+	} else {
+		attempt.Error = res.Err.Error()
+		d.LastError = attempt.Error
+		if d.Attempts >= webhookMaxAttempts {
+			d.Status = model.DeliveryFailed
+		} else {
+			d.NextAttemptAt = now.Add(retry.Backoff(d.Attempts, webhookRetryBase, webhookRetryMax))
+		}
+	}
+	if err := u.repo.RecordAttempt(ctx, attempt, d); err != nil {
+		fmt.Println("Failed to record webhook attempt:", err)
+	}
+}